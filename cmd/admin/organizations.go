@@ -28,13 +28,11 @@ func OrganizationsCmd(imsConfig *ims.Config) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.SilenceUsage = true
 
-
 			resp, err := imsConfig.GetAdminOrganizations()
 			if err != nil {
 				return fmt.Errorf("error in get admin organizations cmd: %w", err)
 			}
-			fmt.Println(prettify.JSON(resp))
-			return nil
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, prettify.RawJSON(resp), prettify.JSON(resp))
 		},
 	}
 	cmd.Flags().StringVarP(&imsConfig.Guid, "guid", "g", "", "User ID.")
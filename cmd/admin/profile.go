@@ -20,6 +20,9 @@ import (
 )
 
 func ProfileCmd(imsConfig *ims.Config) *cobra.Command {
+	var fields []string
+	var query string
+
 	cmd := &cobra.Command{
 		Use:   "profile",
 		Short: "Requests the user profile using the admin API.",
@@ -27,13 +30,15 @@ func ProfileCmd(imsConfig *ims.Config) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.SilenceUsage = true
 
-
-			resp, err := imsConfig.GetAdminProfile()
+			profile, err := imsConfig.GetAdminProfile()
+			if err != nil {
+				return fmt.Errorf("error in get admin profile cmd: %w", err)
+			}
+			data, text, err := prettify.Select(profile, profile.Body, fields, query)
 			if err != nil {
 				return fmt.Errorf("error in get admin profile cmd: %w", err)
 			}
-			fmt.Println(prettify.JSON(resp))
-			return nil
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, text)
 		},
 	}
 	cmd.Flags().StringVarP(&imsConfig.Guid, "guid", "g", "", "User ID.")
@@ -42,5 +47,9 @@ func ProfileCmd(imsConfig *ims.Config) *cobra.Command {
 	cmd.Flags().StringVarP(&imsConfig.ServiceToken, "serviceToken", "t", "", "Service token.")
 	cmd.Flags().StringVarP(&imsConfig.ProfileAPIVersion, "profileApiVersion", "a", "v1", "Admin profile API version.")
 
+	cmd.Flags().StringSliceVar(&fields, "fields", nil, "Top-level fields of the typed profile to print, e.g. email,userId,roles.")
+	cmd.Flags().StringVar(&query, "query", "", "JSONPath-like query selecting a part of the profile, e.g. $.roles[*].named_role.")
+	cmd.MarkFlagsMutuallyExclusive("fields", "query")
+
 	return cmd
 }
@@ -0,0 +1,75 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/adobe/imscli/ims"
+	"github.com/spf13/cobra"
+)
+
+func agentCmd(imsConfig *ims.Config) *cobra.Command {
+	var flow, socket string
+	var refreshBefore time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Hold a token in memory and serve it to local processes.",
+		Long: `Negotiate a token with one of the authorize flows and serve it over a Unix socket, similar to ssh-agent, so
+long running sessions log in once. The token is refreshed before it expires, with the refresh token when the flow
+returns one, and by negotiating a new token otherwise.
+
+The socket is accessible only by the current user. Its path is given by --socket, the IMS_AGENT_SOCK environment
+variable, or defaults to agent.sock in the imscli configuration directory. Use "imscli token get --agent" to get the
+token, or send {"command":"get"} as a line of JSON to the socket. The agent runs until interrupted.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			if err := checkFlow(flow); err != nil {
+				return err
+			}
+			if socket == "" {
+				var err error
+				if socket, err = ims.AgentSocketPath(); err != nil {
+					return err
+				}
+			}
+			listener, err := ims.ListenAgent(socket)
+			if err != nil {
+				return err
+			}
+			agent, err := imsConfig.NewAgent(flow, refreshBefore)
+			if err != nil {
+				_ = listener.Close()
+				return fmt.Errorf("error in %s authorization: %w", flow, err)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Agent listening at %s\n%s=%s; export %s\n", socket,
+				ims.AgentSocketEnv, socket, ims.AgentSocketEnv)
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return agent.Serve(ctx, listener)
+		},
+	}
+
+	cmd.Flags().StringVar(&socket, "socket", "", "Path of the agent socket.")
+	cmd.Flags().DurationVar(&refreshBefore, "refreshBefore", 5*time.Minute,
+		"How long before the token expiration the token is refreshed.")
+	addFlowFlags(cmd, imsConfig, &flow)
+
+	return cmd
+}
@@ -30,6 +30,7 @@ This command has no effect by itself, the authorization type needs to be specifi
 		authz.UserCmd(imsConfig),
 		authz.UserPkceCmd(imsConfig),
 		authz.ImplicitCmd(imsConfig),
+		authz.DeviceCmd(imsConfig),
 		authz.ServiceCmd(imsConfig),
 		authz.JWTCmd(imsConfig),
 		authz.ClientCredentialsCmd(imsConfig),
@@ -30,8 +30,7 @@ func ClientCredentialsCmd(imsConfig *ims.Config) *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("error in login service: %w", err)
 			}
-			fmt.Println(resp)
-			return nil
+			return printToken(cmd, imsConfig, resp)
 		},
 	}
 
@@ -0,0 +1,46 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package authz
+
+import (
+	"fmt"
+
+	"github.com/adobe/imscli/ims"
+	"github.com/spf13/cobra"
+)
+
+func DeviceCmd(imsConfig *ims.Config) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "device",
+		Short: "Negotiate a user access token using the OAuth 2.0 device authorization grant.",
+		Long: "Perform the 'Device Authorization Grant' (RFC 8628). The verification URI and a user code are printed " +
+			"to stderr; complete the login from any device with a browser while the CLI waits for the token. " +
+			"Useful on SSH sessions, containers and CI runners where no local browser is available.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			resp, err := imsConfig.AuthorizeDevice()
+			if err != nil {
+				return fmt.Errorf("error in device authorization: %w", err)
+			}
+			return printToken(cmd, imsConfig, resp)
+		},
+	}
+
+	cmd.Flags().StringVarP(&imsConfig.ClientID, "clientID", "c", "", "IMS client ID.")
+	cmd.Flags().StringVarP(&imsConfig.ClientSecret, "clientSecret", "p", "", "IMS client secret, only for private clients.")
+	cmd.Flags().StringSliceVarP(&imsConfig.Scopes, "scopes", "s", []string{}, "Scopes to request.")
+	cmd.Flags().StringSliceVarP(&imsConfig.Resource, "resource", "r", nil,
+		"RFC 8707 resource indicator URI(s) for audience-restricted tokens.")
+
+	return cmd
+}
@@ -33,8 +33,7 @@ func ImplicitCmd(imsConfig *ims.Config) *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("error in implicit authorization: %w", err)
 			}
-			fmt.Println(resp)
-			return nil
+			return printToken(cmd, imsConfig, resp)
 		},
 	}
 
@@ -13,24 +13,38 @@ package authz
 import (
 	"fmt"
 
+	"github.com/adobe/imscli/cmd/prettify"
 	"github.com/adobe/imscli/ims"
 	"github.com/spf13/cobra"
 )
 
 func JWTCmd(imsConfig *ims.Config) *cobra.Command {
+	var dryRun bool
+
 	cmd := &cobra.Command{
 		Use:   "jwt",
 		Short: "Exchange a JWT for an access token.",
-		Long:  "Perform the 'Assertion Grant Type Flow' to request a token.",
+		Long: "Perform the 'Assertion Grant Type Flow' to request a token. With --dryRun, the signed assertion is " +
+			"printed instead of being exchanged.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.SilenceUsage = true
 
+			if dryRun {
+				assertion, err := imsConfig.SignJWT()
+				if err != nil {
+					return fmt.Errorf("error in jwt authorization: %w", err)
+				}
+				data := struct {
+					Assertion string `json:"assertion"`
+				}{assertion}
+				return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, assertion)
+			}
+
 			resp, err := imsConfig.AuthorizeJWTExchange()
 			if err != nil {
 				return fmt.Errorf("error in jwt authorization: %w", err)
 			}
-			fmt.Println(resp.AccessToken)
-			return nil
+			return printToken(cmd, imsConfig, resp.AccessToken)
 		},
 	}
 
@@ -38,10 +52,14 @@ func JWTCmd(imsConfig *ims.Config) *cobra.Command {
 	cmd.Flags().StringVarP(&imsConfig.ClientSecret, "clientSecret", "p", "", "IMS Client secret.")
 	cmd.Flags().StringVarP(&imsConfig.Organization, "organization", "o", "", "IMS Organization.")
 	cmd.Flags().StringVarP(&imsConfig.Account, "account", "A", "", "Technical Account ID.")
-	cmd.Flags().StringVarP(&imsConfig.PrivateKeyPath, "privateKey", "k", "", "Private Key file.")
+	cmd.Flags().StringVarP(&imsConfig.PrivateKeyPath, "privateKey", "k", "",
+		"Private key file: PEM (PKCS #1, PKCS #8, optionally encrypted) or PKCS #12.")
+	cmd.Flags().StringVar(&imsConfig.PrivateKeyPassphrase, "privateKeyPassphrase", "",
+		"Passphrase of an encrypted private key or PKCS #12 bundle, prompted when missing.")
 	cmd.Flags().StringSliceVarP(&imsConfig.Metascopes, "metascopes", "m", []string{}, "Metascopes to request.")
 	cmd.Flags().StringSliceVarP(&imsConfig.Resource, "resource", "r", nil,
 		"RFC 8707 resource indicator URI(s) for audience-restricted tokens.")
+	cmd.Flags().BoolVar(&dryRun, "dryRun", false, "Print the signed JWT assertion instead of exchanging it.")
 
 	return cmd
 }
@@ -31,8 +31,7 @@ func UserPkceCmd(imsConfig *ims.Config) *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("error in user authorization: %w", err)
 			}
-			fmt.Println(resp)
-			return nil
+			return printAuthorization(cmd, imsConfig, resp)
 		},
 	}
 
@@ -44,6 +43,9 @@ func UserPkceCmd(imsConfig *ims.Config) *cobra.Command {
 	cmd.Flags().IntVarP(&imsConfig.Port, "port", "l", 8888, "Local port to be used by the OAuth Client.")
 	cmd.Flags().StringSliceVarP(&imsConfig.Resource, "resource", "r", nil,
 		"RFC 8707 resource indicator URI(s) for audience-restricted tokens.")
+	addNoBrowserFlag(cmd, imsConfig)
+	cmd.Flags().BoolVarP(&imsConfig.FullOutput, "fullOutput", "F", false,
+		"Output a JSON with the access, refresh and ID tokens, expiration and granted scopes.")
 
 	return cmd
 }
@@ -29,8 +29,7 @@ func ServiceCmd(imsConfig *ims.Config) *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("error in login service: %w", err)
 			}
-			fmt.Println(resp)
-			return nil
+			return printToken(cmd, imsConfig, resp)
 		},
 	}
 
@@ -8,14 +8,18 @@
 // OF ANY KIND, either express or implied. See the License for the specific language
 // governing permissions and limitations under the License.
 
-// Package authz implements the authorize subcommands (user, pkce, service, jwt, client).
+// Package authz implements the authorize subcommands (user, pkce, implicit, device, service, jwt, client).
 package authz
 
 import (
+	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/adobe/imscli/cmd/prettify"
 	"github.com/adobe/imscli/ims"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func UserCmd(imsConfig *ims.Config) *cobra.Command {
@@ -32,8 +36,7 @@ func UserCmd(imsConfig *ims.Config) *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("error in user authorization: %w", err)
 			}
-			fmt.Println(resp)
-			return nil
+			return printAuthorization(cmd, imsConfig, resp)
 		},
 	}
 
@@ -44,6 +47,62 @@ func UserCmd(imsConfig *ims.Config) *cobra.Command {
 	cmd.Flags().IntVarP(&imsConfig.Port, "port", "l", 8888, "Local port to be used by the OAuth Client.")
 	cmd.Flags().StringSliceVarP(&imsConfig.Resource, "resource", "r", nil,
 		"RFC 8707 resource indicator URI(s) for audience-restricted tokens.")
+	addNoBrowserFlag(cmd, imsConfig)
+	cmd.Flags().BoolVarP(&imsConfig.FullOutput, "fullOutput", "F", false,
+		"Output a JSON with the access, refresh and ID tokens, expiration and granted scopes.")
 
 	return cmd
 }
+
+// addNoBrowserFlag adds the --noBrowser flag of the authorization code flows,
+// also accepted as --manual.
+func addNoBrowserFlag(cmd *cobra.Command, imsConfig *ims.Config) {
+	cmd.Flags().BoolVar(&imsConfig.NoBrowser, "noBrowser", false,
+		"Print the authorize URL instead of launching a browser, and read the redirect URL or code from stdin "+
+			"(alias --manual).")
+	cmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "manual" {
+			name = "noBrowser"
+		}
+		return pflag.NormalizedName(name)
+	})
+}
+
+// printToken prints the access token negotiated by a flow that returns no
+// other information.
+func printToken(cmd *cobra.Command, imsConfig *ims.Config, token string) error {
+	data := struct {
+		AccessToken string `json:"access_token"`
+	}{token}
+	return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, token)
+}
+
+// printAuthorization prints the access token, or the full token response as
+// JSON when --fullOutput is set.
+func printAuthorization(cmd *cobra.Command, imsConfig *ims.Config, resp ims.AuthorizationInfo) error {
+	data := struct {
+		AccessToken  string   `json:"access_token"`
+		RefreshToken string   `json:"refresh_token,omitempty"`
+		IDToken      string   `json:"id_token,omitempty"`
+		ExpiresAt    string   `json:"expires_at,omitempty"`
+		Scopes       []string `json:"scopes,omitempty"`
+	}{
+		AccessToken:  resp.AccessToken,
+		RefreshToken: resp.RefreshToken,
+		IDToken:      resp.IDToken,
+		Scopes:       resp.Scopes,
+	}
+	if !resp.ExpiresAt.IsZero() {
+		data.ExpiresAt = resp.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	text := resp.AccessToken
+	if imsConfig.FullOutput {
+		jsonData, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshalling full JSON response: %w", err)
+		}
+		text = string(jsonData)
+	}
+	return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, text)
+}
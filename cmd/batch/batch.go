@@ -0,0 +1,105 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+// Package batch implements the batch mode of the validate and invalidate
+// subcommands, applying the operation to the tokens listed in a file.
+package batch
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/adobe/imscli/cmd/prettify"
+	"github.com/adobe/imscli/ims"
+	"github.com/spf13/cobra"
+)
+
+// Operation is the operation applied to each token of the batch.
+type Operation struct {
+	// Verb and Participle name the operation, e.g. validate and validated.
+	Verb       string
+	Participle string
+	// Noun names the operation in the error of the command, e.g. validation.
+	Noun string
+	// Done is the state of the tokens the operation succeeded for, e.g. valid.
+	Done string
+	// Failure completes "the command fails when any token", e.g. "cannot be validated".
+	Failure string
+	// Run applies the operation to the tokens.
+	Run func(c ims.Config, tokens []ims.BatchToken, concurrency int) (ims.BatchReport, error)
+}
+
+// Help documents the batch mode in the long help of the subcommands.
+func (op Operation) Help() string {
+	return fmt.Sprintf(`With --fromFile, the tokens are read from a file, or stdin with "-", one per line: either the bare
+token, or a JSON object with the token and optionally its type, e.g. {"token": "...", "type": "refreshToken"}.
+The bare tokens are of the type of the subcommand. A report of each token is printed and the command
+fails when any token %s.`, op.Failure)
+}
+
+// Mode holds the flags of the batch mode of a subcommand.
+type Mode struct {
+	op          Operation
+	fromFile    string
+	concurrency int
+}
+
+// AddFlags adds the flags of the batch mode to the subcommand.
+func AddFlags(cmd *cobra.Command, op Operation) *Mode {
+	m := &Mode{op: op}
+	cmd.Flags().StringVar(&m.fromFile, "fromFile", "",
+		fmt.Sprintf("File listing the tokens to %s, or stdin with \"-\".", op.Verb))
+	cmd.Flags().IntVar(&m.concurrency, "concurrency", 8,
+		fmt.Sprintf("Maximum number of tokens of --fromFile %s simultaneously.", op.Participle))
+	return m
+}
+
+// Enabled reports whether the tokens are read from a file.
+func (m *Mode) Enabled() bool {
+	return m.fromFile != ""
+}
+
+// Run applies the operation to the tokens of the file, the bare tokens being
+// of the given type, and reports the failures through the returned error. The
+// flag of the single token of the subcommand cannot be combined with the file.
+func (m *Mode) Run(cmd *cobra.Command, imsConfig ims.Config, tokenType, tokenFlag string) error {
+	if cmd.Flags().Changed(tokenFlag) {
+		return fmt.Errorf("--%s cannot be combined with --fromFile", tokenFlag)
+	}
+	tokens, err := ims.ReadBatchTokens(m.fromFile, cmd.InOrStdin(), tokenType)
+	if err != nil {
+		return fmt.Errorf("error reading the tokens: %w", err)
+	}
+	report, err := m.op.Run(imsConfig, tokens, m.concurrency)
+	if err != nil {
+		return fmt.Errorf("error during the token %s: %w", m.op.Noun, err)
+	}
+	if err := prettify.Render(cmd.OutOrStdout(), imsConfig.Output, report, m.text(report)); err != nil {
+		return err
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d tokens failed %s", report.Failed, report.Total, m.op.Noun)
+	}
+	return nil
+}
+
+// text formats the report as a table, one token per row.
+func (m *Mode) text(report ims.BatchReport) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "LINE\tTYPE\tTOKEN\tSTATUS\tREASON")
+	for _, r := range report.Results {
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", r.Line, r.Type, r.Token, r.Status, r.Reason)
+	}
+	_ = w.Flush()
+	_, _ = fmt.Fprintf(&b, "%d of %d tokens %s.", report.Total-report.Failed, report.Total, m.op.Done)
+	return b.String()
+}
@@ -0,0 +1,37 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package cmd
+
+import (
+	"github.com/adobe/imscli/cmd/cache"
+	"github.com/adobe/imscli/ims"
+	"github.com/spf13/cobra"
+)
+
+func cacheCmd(imsConfig *ims.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the local token cache.",
+		Long: `The cache command inspects and cleans the local token cache.
+
+Tokens negotiated by the authorize subcommands are cached on disk and reused while they are valid. Use the global
+--noCache flag to bypass the cache.
+
+This command has no effect by itself, the operation needs to be specified as a subcommand.
+`,
+	}
+	cmd.AddCommand(
+		cache.ListCmd(imsConfig),
+		cache.ShowCmd(imsConfig),
+		cache.PurgeCmd(imsConfig),
+	)
+	return cmd
+}
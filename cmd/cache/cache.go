@@ -0,0 +1,126 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+// Package cache implements the cache subcommands (list, show, purge).
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/adobe/imscli/cmd/prettify"
+	"github.com/adobe/imscli/ims"
+	"github.com/spf13/cobra"
+)
+
+// shortKeyLength is the number of key characters displayed by the list
+// subcommand, enough to be unambiguous for the show subcommand.
+const shortKeyLength = 12
+
+func ListCmd(imsConfig *ims.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the cached tokens.",
+		Long:    "List the cached tokens without displaying them.",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			entries, err := ims.ListCachedTokens()
+			if err != nil {
+				return fmt.Errorf("error listing the token cache: %w", err)
+			}
+
+			type listEntry struct {
+				Key          string    `json:"key"`
+				Flow         string    `json:"flow"`
+				URL          string    `json:"url"`
+				ClientID     string    `json:"client_id"`
+				Organization string    `json:"organization,omitempty"`
+				Scopes       []string  `json:"scopes,omitempty"`
+				ExpiresAt    time.Time `json:"expires_at"`
+				Expired      bool      `json:"expired"`
+			}
+			data := make([]listEntry, 0, len(entries))
+
+			var text strings.Builder
+			w := tabwriter.NewWriter(&text, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "KEY\tFLOW\tCLIENT ID\tORGANIZATION\tSCOPES\tEXPIRES")
+			for _, e := range entries {
+				data = append(data, listEntry{e.Key, e.Flow, e.URL, e.ClientID, e.Organization, e.Scopes, e.ExpiresAt, e.Expired()})
+
+				expires := e.ExpiresAt.Format(time.RFC3339)
+				if e.Expired() {
+					expires += " (expired)"
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Key[:shortKeyLength], e.Flow, e.ClientID,
+					e.Organization, strings.Join(e.Scopes, ","), expires)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, strings.TrimSuffix(text.String(), "\n"))
+		},
+	}
+	return cmd
+}
+
+func ShowCmd(imsConfig *ims.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show <key>",
+		Short: "Show a cached token.",
+		Long:  "Show a cached token and its metadata. Any unambiguous prefix of the key is accepted.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			entry, err := ims.GetCachedToken(args[0])
+			if err != nil {
+				return fmt.Errorf("error reading the token cache: %w", err)
+			}
+			jsonData, err := json.MarshalIndent(entry, "", "  ")
+			if err != nil {
+				return fmt.Errorf("error marshalling the cache entry: %w", err)
+			}
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, entry, string(jsonData))
+		},
+	}
+	return cmd
+}
+
+func PurgeCmd(imsConfig *ims.Config) *cobra.Command {
+	var expired bool
+
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Delete the cached tokens.",
+		Long:  "Delete all the cached tokens, or only the expired ones.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			n, err := ims.PurgeTokenCache(expired)
+			if err != nil {
+				return fmt.Errorf("error purging the token cache: %w", err)
+			}
+			data := struct {
+				Deleted int `json:"deleted"`
+			}{n}
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, fmt.Sprintf("%d cached token(s) deleted.", n))
+		},
+	}
+
+	cmd.Flags().BoolVarP(&expired, "expired", "e", false, "Only delete expired tokens.")
+	return cmd
+}
@@ -0,0 +1,206 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// Keys of the configuration file used by the named contexts.
+const (
+	contextKey        = "context"
+	currentContextKey = "current-context"
+	contextsKey       = "contexts"
+)
+
+// configFilePath returns the configuration file read by initParams: the
+// explicit one, the first imscli.* file found in the search paths, or the
+// default ~/.config/imscli.yaml when there is none yet.
+func configFilePath(configFile string) (string, error) {
+	if configFile != "" {
+		return configFile, nil
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find configuration directory: %w", err)
+	}
+
+	v := viper.New()
+	v.AddConfigPath(".")
+	v.AddConfigPath(configDir)
+	v.SetConfigName("imscli")
+	err = v.ReadInConfig()
+	if err == nil {
+		return v.ConfigFileUsed(), nil
+	}
+	if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+		return "", fmt.Errorf("unable to read configuration file: %w", err)
+	}
+	return filepath.Join(configDir, "imscli.yaml"), nil
+}
+
+// configDocument is a configuration file edited by imscli. YAML files are
+// edited through their node tree, so comments and key order are preserved.
+// JSON files, being valid YAML, are parsed the same way and re-encoded as JSON
+// when saved.
+type configDocument struct {
+	path string
+	root *yaml.Node
+}
+
+func loadConfigDocument(path string) (*configDocument, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return nil, fmt.Errorf("editing %s configuration files is not supported, use YAML or JSON", filepath.Ext(path))
+	}
+
+	doc := &configDocument{path: path, root: &yaml.Node{Kind: yaml.MappingNode}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return doc, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read configuration file: %w", err)
+	}
+
+	var file yaml.Node
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("unable to parse configuration file: %w", err)
+	}
+	if len(file.Content) > 0 {
+		if file.Content[0].Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("unable to parse configuration file: the document is not a map")
+		}
+		doc.root = file.Content[0]
+	}
+	return doc, nil
+}
+
+// lookup returns the key and value nodes of a mapping entry, matching the
+// key case-insensitively like viper does.
+func lookup(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if strings.EqualFold(mapping.Content[i].Value, key) {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// get returns the value at the given path of keys, or nil.
+func (d *configDocument) get(path ...string) *yaml.Node {
+	node := d.root
+	for _, key := range path {
+		_, node = lookup(node, key)
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// set stores the value at the given path of keys, creating the intermediate
+// maps as needed.
+func (d *configDocument) set(value any, path ...string) error {
+	var encoded yaml.Node
+	if err := encoded.Encode(value); err != nil {
+		return fmt.Errorf("unable to encode configuration value: %w", err)
+	}
+
+	node := d.root
+	for i, key := range path {
+		last := i == len(path)-1
+		_, child := lookup(node, key)
+		switch {
+		case child != nil && last:
+			*child = encoded
+		case child != nil:
+			if child.Kind != yaml.MappingNode {
+				return fmt.Errorf("configuration key %s is not a map", key)
+			}
+		default:
+			child = &yaml.Node{Kind: yaml.MappingNode}
+			if last {
+				child = &encoded
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, child)
+		}
+		node = child
+	}
+	return nil
+}
+
+// delete removes the value at the given path of keys, if present.
+func (d *configDocument) delete(path ...string) {
+	parent := d.root
+	if len(path) > 1 {
+		parent = d.get(path[:len(path)-1]...)
+	}
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if strings.EqualFold(parent.Content[i].Value, path[len(path)-1]) {
+			parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// save writes the document readable only by the current user, since the
+// configuration file may hold client secrets.
+func (d *configDocument) save() error {
+	var data []byte
+	if strings.EqualFold(filepath.Ext(d.path), ".json") {
+		var v any
+		if err := d.root.Decode(&v); err != nil {
+			return fmt.Errorf("unable to encode configuration file: %w", err)
+		}
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to encode configuration file: %w", err)
+		}
+		data = append(b, '\n')
+	} else {
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(d.root); err != nil {
+			return fmt.Errorf("unable to encode configuration file: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return fmt.Errorf("unable to encode configuration file: %w", err)
+		}
+		data = buf.Bytes()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(d.path), 0o700); err != nil {
+		return fmt.Errorf("unable to create configuration directory: %w", err)
+	}
+	if err := os.WriteFile(d.path, data, 0o600); err != nil {
+		return fmt.Errorf("unable to write configuration file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,341 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/adobe/imscli/cmd/prettify"
+	"github.com/adobe/imscli/ims"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+// skipContextAnnotation marks the commands that keep working when the selected
+// context does not exist, so a dangling current-context can be fixed.
+const skipContextAnnotation = "imscli/skip-context"
+
+// maskedSecret replaces the secrets in the output of context show.
+const maskedSecret = "********"
+
+// contextNameRegexp restricts context names to characters that viper does not
+// interpret as key separators.
+var contextNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// contextSettings lists the flags stored by context create, in the order they
+// are written to the configuration file.
+var contextSettings = []string{"url", "clientID", "clientSecret", "organization", "scopes", "port", "proxyUrl"}
+
+// skipsContext reports whether the command or any of its parents is annotated
+// with skipContextAnnotation.
+func skipsContext(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if _, ok := c.Annotations[skipContextAnnotation]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func contextCmd(configFile *string, imsConfig *ims.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "context",
+		Short: "Manage the named contexts of the configuration file.",
+		Long: `The context command manages named contexts, sets of parameters (URL, client ID, client secret,
+organization, scopes, port and proxy) stored under the contexts key of the configuration file.
+
+The context selected with the global --context flag, the IMS_CONTEXT environment variable or the current-context key of
+the configuration file is layered on top of the configuration file, below environment variables and flags.
+
+This command has no effect by itself, the operation needs to be specified as a subcommand.
+`,
+		Annotations: map[string]string{skipContextAnnotation: ""},
+	}
+	cmd.AddCommand(
+		contextListCmd(configFile, imsConfig),
+		contextUseCmd(configFile),
+		contextShowCmd(configFile, imsConfig),
+		contextCreateCmd(configFile, imsConfig),
+		contextDeleteCmd(configFile),
+	)
+	return cmd
+}
+
+// loadContexts loads the configuration file and returns its contexts map,
+// which is nil when the file has no contexts.
+func loadContexts(configFile string) (*configDocument, *yaml.Node, error) {
+	path, err := configFilePath(configFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, err := loadConfigDocument(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	contexts := doc.get(contextsKey)
+	if contexts != nil && contexts.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("the %s key of the configuration file is not a map", contextsKey)
+	}
+	return doc, contexts, nil
+}
+
+// findContext returns the name of the context as written in the configuration
+// file, matching it case-insensitively.
+func findContext(contexts *yaml.Node, name string) (string, bool) {
+	key, _ := lookup(contexts, name)
+	if key == nil {
+		return "", false
+	}
+	return key.Value, true
+}
+
+// newContextDocument loads the configuration file where a context is about to
+// be created, checking that the name is valid and not taken.
+func newContextDocument(configFile, name string) (*configDocument, error) {
+	if !contextNameRegexp.MatchString(name) {
+		return nil, fmt.Errorf("invalid context name %q, only letters, digits, '-' and '_' are allowed", name)
+	}
+	doc, contexts, err := loadContexts(configFile)
+	if err != nil {
+		return nil, err
+	}
+	if existing, ok := findContext(contexts, name); ok {
+		return nil, fmt.Errorf("context %q already exists", existing)
+	}
+	return doc, nil
+}
+
+func currentContext(doc *configDocument) string {
+	if node := doc.get(currentContextKey); node != nil && node.Kind == yaml.ScalarNode {
+		return node.Value
+	}
+	return ""
+}
+
+func contextListCmd(configFile *string, imsConfig *ims.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the contexts.",
+		Long:    "List the contexts of the configuration file, marking the current one with an asterisk.",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			doc, contexts, err := loadContexts(*configFile)
+			if err != nil {
+				return err
+			}
+			current := currentContext(doc)
+
+			type listEntry struct {
+				Name    string `json:"name"`
+				Current bool   `json:"current"`
+			}
+			data := []listEntry{}
+			var lines []string
+			if contexts != nil {
+				for i := 0; i < len(contexts.Content); i += 2 {
+					name := contexts.Content[i].Value
+					isCurrent := strings.EqualFold(name, current)
+					data = append(data, listEntry{name, isCurrent})
+					if isCurrent {
+						lines = append(lines, "* "+name)
+					} else {
+						lines = append(lines, "  "+name)
+					}
+				}
+			}
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, strings.Join(lines, "\n"))
+		},
+	}
+	return cmd
+}
+
+func contextUseCmd(configFile *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "use <name>",
+		Short: "Set the current context.",
+		Long:  "Set the context used when no --context flag or IMS_CONTEXT environment variable is given.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			doc, contexts, err := loadContexts(*configFile)
+			if err != nil {
+				return err
+			}
+			name, ok := findContext(contexts, args[0])
+			if !ok {
+				return fmt.Errorf("context %q not found in the configuration file", args[0])
+			}
+			if err := doc.set(name, currentContextKey); err != nil {
+				return err
+			}
+			if err := doc.save(); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.ErrOrStderr(), "Switched to context %s.\n", name)
+			return err
+		},
+	}
+	return cmd
+}
+
+func contextShowCmd(configFile *string, imsConfig *ims.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show [name]",
+		Short: "Show the parameters of a context.",
+		Long:  "Show the parameters of the given context, or of the current one. The secrets are masked.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			doc, contexts, err := loadContexts(*configFile)
+			if err != nil {
+				return err
+			}
+			name := currentContext(doc)
+			if len(args) > 0 {
+				name = args[0]
+			}
+			if name == "" {
+				return fmt.Errorf("missing context name parameter, no current context is set")
+			}
+
+			_, node := lookup(contexts, name)
+			if node == nil {
+				return fmt.Errorf("context %q not found in the configuration file", name)
+			}
+			var settings map[string]any
+			if err := node.Decode(&settings); err != nil {
+				return fmt.Errorf("unable to parse context %s: %w", name, err)
+			}
+			for k := range settings {
+				if slices.ContainsFunc(secretKeys, func(key string) bool { return strings.EqualFold(k, key) }) {
+					settings[k] = maskedSecret
+				}
+			}
+
+			var text strings.Builder
+			enc := yaml.NewEncoder(&text)
+			enc.SetIndent(2)
+			if err := enc.Encode(settings); err != nil {
+				return fmt.Errorf("unable to encode context %s: %w", name, err)
+			}
+			if err := enc.Close(); err != nil {
+				return fmt.Errorf("unable to encode context %s: %w", name, err)
+			}
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, settings, strings.TrimSuffix(text.String(), "\n"))
+		},
+	}
+	return cmd
+}
+
+func contextCreateCmd(configFile *string, imsConfig *ims.Config) *cobra.Command {
+	var use bool
+
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a context.",
+		Long: "Create a context with the parameters given as flags. Only the flags set on the command line are " +
+			"stored, the global --url and --proxyUrl flags included. Secret references, e.g. env:NAME or " +
+			"keyring:name, are stored as given.",
+		Args:        cobra.ExactArgs(1),
+		Annotations: map[string]string{keepSecretRefsAnnotation: ""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			name := args[0]
+			doc, err := newContextDocument(*configFile, name)
+			if err != nil {
+				return err
+			}
+
+			values := map[string]any{
+				"url":          imsConfig.URL,
+				"clientID":     imsConfig.ClientID,
+				"clientSecret": imsConfig.ClientSecret,
+				"organization": imsConfig.Organization,
+				"scopes":       imsConfig.Scopes,
+				"port":         imsConfig.Port,
+				"proxyUrl":     imsConfig.ProxyURL,
+			}
+			stored := 0
+			for _, key := range contextSettings {
+				if !cmd.Flags().Changed(key) {
+					continue
+				}
+				if err := doc.set(values[key], contextsKey, name, key); err != nil {
+					return err
+				}
+				stored++
+			}
+			if stored == 0 {
+				return fmt.Errorf("missing context parameters, set at least one of --%s", strings.Join(contextSettings, ", --"))
+			}
+			if use {
+				if err := doc.set(name, currentContextKey); err != nil {
+					return err
+				}
+			}
+			if err := doc.save(); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.ErrOrStderr(), "Context %s created in %s.\n", name, doc.path)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&imsConfig.ClientID, "clientID", "c", "", "IMS client ID.")
+	cmd.Flags().StringVarP(&imsConfig.ClientSecret, "clientSecret", "p", "", "IMS client secret.")
+	cmd.Flags().StringVarP(&imsConfig.Organization, "organization", "o", "", "IMS Organization.")
+	cmd.Flags().StringSliceVarP(&imsConfig.Scopes, "scopes", "s", []string{}, "Scopes to request.")
+	cmd.Flags().IntVarP(&imsConfig.Port, "port", "l", 8888, "Local port to be used by the OAuth Client.")
+	cmd.Flags().BoolVar(&use, "use", false, "Set the new context as the current context.")
+
+	return cmd
+}
+
+func contextDeleteCmd(configFile *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delete <name>",
+		Aliases: []string{"rm"},
+		Short:   "Delete a context.",
+		Long:    "Delete a context from the configuration file. Deleting the current context unsets it.",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			doc, contexts, err := loadContexts(*configFile)
+			if err != nil {
+				return err
+			}
+			name, ok := findContext(contexts, args[0])
+			if !ok {
+				return fmt.Errorf("context %q not found in the configuration file", args[0])
+			}
+			doc.delete(contextsKey, name)
+			if strings.EqualFold(currentContext(doc), name) {
+				doc.delete(currentContextKey)
+			}
+			if err := doc.save(); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.ErrOrStderr(), "Context %s deleted.\n", name)
+			return err
+		},
+	}
+	return cmd
+}
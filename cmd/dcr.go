@@ -12,38 +12,96 @@ package cmd
 
 import (
 	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/adobe/imscli/cmd/prettify"
 	"github.com/adobe/imscli/ims"
+	"github.com/adobe/imscli/secretstore"
 	"github.com/spf13/cobra"
 )
 
-func dcrCmd(imsConfig *ims.Config) *cobra.Command {
+func dcrCmd(configFile *string, imsConfig *ims.Config) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "dcr",
 		Short: "Dynamic Client Registration operations.",
-		Long:  `The dcr command enables Dynamic Client Registration operations.`,
+		Long: `The dcr command enables Dynamic Client Registration operations.
+
+A registered client is read, updated and deleted with the registration access token returned at registration,
+on the registration client URI also returned at registration.`,
 	}
 	cmd.AddCommand(
-		registerCmd(imsConfig),
+		dcrRegisterCmd(configFile, imsConfig),
+		dcrGetCmd(imsConfig),
+		dcrUpdateCmd(imsConfig),
+		dcrDeleteCmd(imsConfig),
 	)
 	return cmd
 }
 
-func registerCmd(imsConfig *ims.Config) *cobra.Command {
+// dcrSaveOptions holds the flags saving a registered client in a context.
+type dcrSaveOptions struct {
+	name         string
+	storeSecrets bool
+	use          bool
+}
+
+func dcrRegisterCmd(configFile *string, imsConfig *ims.Config) *cobra.Command {
+	var save dcrSaveOptions
+
 	cmd := &cobra.Command{
 		Use:   "register",
 		Short: "Register a client.",
-		Long:  `Register a new OAuth client using Dynamic Client Registration.`,
+		Long: `Register a new OAuth client using Dynamic Client Registration.
+
+The client metadata of RFC 7591 are given as flags, or as a JSON or YAML document with --metadata using the field names
+of the RFC (client_name, redirect_uris, grant_types...), overridden by the flags. They are checked before being sent.
+
+With --save, the client is also saved in a new context of the configuration file: URL, client ID and secret, redirect
+URIs, scopes, the local port of a localhost redirect URI, and the registration access token and URI. The client
+secret and the registration access token are kept in the secret store, as <context>-clientSecret and
+<context>-registrationAccessToken, and referenced from the context. With --storeSecrets=false, they are written in
+plaintext to the configuration file instead.`,
+		Example: `  imscli dcr register --clientName app --redirectURIs http://localhost:8888 --scopes openid --save app --use
+  imscli authorize pkce`,
+		Annotations: map[string]string{keepSecretRefsAnnotation: ""},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.SilenceUsage = true
 
-			resp, err := imsConfig.DCRRegister()
+			// Check the context and open the secret store before registering, not to leave an unsaved
+			// client behind.
+			var doc *configDocument
+			var store secretstore.SecretStore
+			if save.name != "" {
+				var err error
+				if doc, err = newContextDocument(*configFile, save.name); err != nil {
+					return err
+				}
+				if save.storeSecrets {
+					store, err = openSecretStore(cmd, imsConfig.SecretStore, imsConfig.SecretPassphrase)
+					if err != nil {
+						return fmt.Errorf("unable to open the secret store, use --storeSecrets=false to save the "+
+							"secrets in the configuration file: %w", err)
+					}
+				}
+			}
+
+			client, err := imsConfig.DCRRegister()
 			if err != nil {
 				return fmt.Errorf("error during client registration: %w", err)
 			}
 
-			fmt.Println(prettify.JSON(resp))
+			// The client is printed first, so its credentials are not lost if it cannot be saved.
+			if err := renderDCRClient(cmd, imsConfig.Output, client); err != nil {
+				return err
+			}
+			if doc != nil {
+				if err := saveDCRContext(cmd, doc, store, save, *imsConfig, client); err != nil {
+					return fmt.Errorf("the client %s is registered but could not be saved: %w", client.ClientID, err)
+				}
+			}
 			return nil
 		},
 	}
@@ -51,6 +109,211 @@ func registerCmd(imsConfig *ims.Config) *cobra.Command {
 	cmd.Flags().StringVarP(&imsConfig.ClientName, "clientName", "n", "", "Client application name.")
 	cmd.Flags().StringSliceVarP(&imsConfig.RedirectURIs, "redirectURIs", "r", []string{}, "Redirect URIs (comma-separated or multiple flags).")
 	cmd.Flags().StringSliceVarP(&imsConfig.Scopes, "scopes", "s", []string{}, "Requested scopes (comma-separated or multiple flags).")
+	cmd.Flags().StringSliceVar(&imsConfig.GrantTypes, "grantTypes", []string{},
+		"Grant types, e.g. authorization_code,refresh_token (authorization_code by default).")
+	cmd.Flags().StringSliceVar(&imsConfig.ResponseTypes, "responseTypes", []string{},
+		"Response types, e.g. code (code by default).")
+	cmd.Flags().StringVar(&imsConfig.TokenEndpointAuthMethod, "tokenEndpointAuthMethod", "",
+		"Token endpoint authentication method: none, client_secret_post, client_secret_basic, private_key_jwt...")
+	cmd.Flags().StringVar(&imsConfig.ClientURI, "clientURI", "", "URL of the home page of the client.")
+	cmd.Flags().StringVar(&imsConfig.LogoURI, "logoURI", "", "URL of the logo of the client.")
+	cmd.Flags().StringVar(&imsConfig.TosURI, "tosURI", "", "URL of the terms of service of the client.")
+	cmd.Flags().StringVar(&imsConfig.PolicyURI, "policyURI", "", "URL of the privacy policy of the client.")
+	cmd.Flags().StringSliceVar(&imsConfig.Contacts, "contacts", []string{},
+		"Email addresses of the people responsible for the client.")
+	cmd.Flags().StringVar(&imsConfig.JWKSURI, "jwksURI", "", "URL of the JWKS holding the public keys of the client.")
+	cmd.Flags().StringVar(&imsConfig.SoftwareID, "softwareID", "", "Identifier of the client software.")
+	cmd.Flags().StringVar(&imsConfig.SoftwareVersion, "softwareVersion", "", "Version of the client software.")
+	cmd.Flags().StringVar(&imsConfig.SoftwareStatement, "softwareStatement", "",
+		"Software statement, a JWT asserting the metadata of the client software.")
+	cmd.Flags().StringVar(&imsConfig.Metadata, "metadata", "",
+		"JSON or YAML document of RFC 7591 client metadata, overridden by the other flags.")
+	cmd.Flags().StringVar(&save.name, "save", "", "Save the registered client in a new context with this name.")
+	cmd.Flags().BoolVar(&save.storeSecrets, "storeSecrets", true,
+		"Keep the secrets of the saved client in the secret store, false to write them to the configuration file.")
+	cmd.Flags().BoolVar(&save.use, "use", false, "Set the saved context as the current context.")
+
+	return cmd
+}
+
+// saveDCRContext creates the context of a registered client. The secrets are
+// kept in the store, or in the context when it is nil.
+func saveDCRContext(cmd *cobra.Command, doc *configDocument, store secretstore.SecretStore, save dcrSaveOptions,
+	imsConfig ims.Config, client ims.DCRClient) error {
+
+	redirectURIs := client.RedirectURIs
+	if len(redirectURIs) == 0 {
+		redirectURIs = imsConfig.RedirectURIs
+	}
+	scopes := strings.Fields(client.Scope)
+	if len(scopes) == 0 {
+		scopes = imsConfig.Scopes
+	}
+	settings := []struct {
+		key   string
+		value any
+	}{
+		{"url", imsConfig.URL},
+		{"clientID", client.ClientID},
+		{"clientSecret", client.ClientSecret},
+		{"redirectURIs", redirectURIs},
+		{"scopes", scopes},
+		{"port", localRedirectPort(redirectURIs)},
+		{"registrationAccessToken", client.RegistrationAccessToken},
+		{"registrationClientURI", client.RegistrationClientURI},
+	}
+
+	var plaintext []string
+	for _, s := range settings {
+		value := s.value
+		switch v := value.(type) {
+		case string:
+			if v == "" {
+				continue
+			}
+			if !slices.Contains(secretKeys, s.key) {
+				break
+			}
+			if store == nil {
+				plaintext = append(plaintext, s.key)
+				break
+			}
+			name := save.name + "-" + s.key
+			if err := store.Set(name, v); err != nil {
+				return fmt.Errorf("error storing the secret %s: %w", name, err)
+			}
+			value = keyringSecretPrefix + name
+		case []string:
+			if len(v) == 0 {
+				continue
+			}
+		case int:
+			if v == 0 {
+				continue
+			}
+		}
+		if err := doc.set(value, contextsKey, save.name, s.key); err != nil {
+			return err
+		}
+	}
+	if save.use {
+		if err := doc.set(save.name, currentContextKey); err != nil {
+			return err
+		}
+	}
+	if err := doc.save(); err != nil {
+		return err
+	}
+	// Printed even without --verbose, unlike the log.
+	if len(plaintext) > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s of context %s written in plaintext to %s.\n",
+			strings.Join(plaintext, " and "), save.name, doc.path)
+	}
+	_, err := fmt.Fprintf(cmd.ErrOrStderr(), "Client %s saved in context %s of %s.\n", client.ClientID, save.name, doc.path)
+	return err
+}
+
+// localRedirectPort returns the port of the first localhost redirect URI,
+// where the user and PKCE flows listen, or 0 if there is none.
+func localRedirectPort(redirectURIs []string) int {
+	for _, uri := range redirectURIs {
+		u, err := url.Parse(uri)
+		if err != nil || u.Scheme != "http" {
+			continue
+		}
+		switch u.Hostname() {
+		case "localhost", "127.0.0.1", "::1":
+			if port, err := strconv.Atoi(u.Port()); err == nil {
+				return port
+			}
+		}
+	}
+	return 0
+}
+
+func dcrGetCmd(imsConfig *ims.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Read a client registration.",
+		Long:  `Read the registration of a client using its registration access token.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			client, err := imsConfig.DCRGet()
+			if err != nil {
+				return fmt.Errorf("error reading the client registration: %w", err)
+			}
+
+			return renderDCRClient(cmd, imsConfig.Output, client)
+		},
+	}
+
+	addDCRClientFlags(cmd, imsConfig)
+
+	return cmd
+}
+
+func dcrUpdateCmd(imsConfig *ims.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update a client registration.",
+		Long: `Update the name, redirect URIs or scopes of a client using its registration access token.
+
+The registration is read first and the metadata not given as flags are sent unchanged.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			client, err := imsConfig.DCRUpdate()
+			if err != nil {
+				return fmt.Errorf("error updating the client registration: %w", err)
+			}
+
+			return renderDCRClient(cmd, imsConfig.Output, client)
+		},
+	}
+
+	addDCRClientFlags(cmd, imsConfig)
+	cmd.Flags().StringVarP(&imsConfig.ClientName, "clientName", "n", "", "New client application name.")
+	cmd.Flags().StringSliceVarP(&imsConfig.RedirectURIs, "redirectURIs", "r", []string{}, "New redirect URIs (comma-separated or multiple flags).")
+	cmd.Flags().StringSliceVarP(&imsConfig.Scopes, "scopes", "s", []string{}, "New scopes (comma-separated or multiple flags).")
 
 	return cmd
 }
+
+func dcrDeleteCmd(imsConfig *ims.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a client registration.",
+		Long:  `Delete the registration of a client using its registration access token.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			if err := imsConfig.DCRDelete(); err != nil {
+				return fmt.Errorf("error deleting the client registration: %w", err)
+			}
+			data := struct {
+				Deleted bool `json:"deleted"`
+			}{true}
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, "Client registration deleted successfully.")
+		},
+	}
+
+	addDCRClientFlags(cmd, imsConfig)
+
+	return cmd
+}
+
+// addDCRClientFlags adds the flags locating a registered client.
+func addDCRClientFlags(cmd *cobra.Command, imsConfig *ims.Config) {
+	cmd.Flags().StringVarP(&imsConfig.ClientID, "clientID", "c", "", "IMS client ID.")
+	cmd.Flags().StringVarP(&imsConfig.RegistrationAccessToken, "registrationAccessToken", "t", "",
+		"Registration access token returned at registration.")
+	cmd.Flags().StringVar(&imsConfig.RegistrationClientURI, "registrationClientURI", "",
+		"Registration client URI returned at registration, <url>/ims/register/<client-id> by default.")
+}
+
+// renderDCRClient prints the registration as returned by IMS, including the
+// metadata not known to imscli.
+func renderDCRClient(cmd *cobra.Command, format string, client ims.DCRClient) error {
+	return prettify.Render(cmd.OutOrStdout(), format, prettify.RawJSON(client.Body), prettify.JSON(client.Body))
+}
@@ -14,6 +14,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/adobe/imscli/cmd/prettify"
@@ -22,11 +23,15 @@ import (
 )
 
 func decodeCmd(imsConfig *ims.Config) *cobra.Command {
+	var verify bool
+
 	cmd := &cobra.Command{
 		Use:     "decode",
 		Aliases: []string{"dec"},
 		Short:   "Decode a JWT token.",
-		Long:    "Decode a JWT token and display the header and payload as prettified JSON.",
+		Long: "Decode a JWT token and display the header and payload as prettified JSON. With --verify, the " +
+			"signature is verified locally with the IMS public keys, which are cached on disk, and the exp, nbf " +
+			"and iat claims are checked.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.SilenceUsage = true
 
@@ -35,8 +40,36 @@ func decodeCmd(imsConfig *ims.Config) *cobra.Command {
 				return fmt.Errorf("error decoding the token: %w", err)
 			}
 
-			output := fmt.Sprintf(`{"header":%s,"payload":%s}`, decoded.Header, decoded.Payload)
-			fmt.Println(prettify.JSON(output))
+			if !verify {
+				data := struct {
+					Header  any `json:"header"`
+					Payload any `json:"payload"`
+				}{prettify.RawJSON(decoded.Header), prettify.RawJSON(decoded.Payload)}
+				output := fmt.Sprintf(`{"header":%s,"payload":%s}`, decoded.Header, decoded.Payload)
+				if err := prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, prettify.JSON(output)); err != nil {
+					return err
+				}
+			} else {
+				verification, err := imsConfig.VerifyToken()
+				if err != nil {
+					return fmt.Errorf("error verifying the token: %w", err)
+				}
+				data := struct {
+					Header       any                `json:"header"`
+					Payload      any                `json:"payload"`
+					Verification verificationOutput `json:"verification"`
+				}{prettify.RawJSON(decoded.Header), prettify.RawJSON(decoded.Payload), newVerificationOutput(verification)}
+				b, err := json.Marshal(data)
+				if err != nil {
+					return fmt.Errorf("error encoding the verification result: %w", err)
+				}
+				if err := prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, prettify.JSON(string(b))); err != nil {
+					return err
+				}
+				if !verification.Valid {
+					return fmt.Errorf("token verification failed: %s", strings.Join(verification.Errors, ", "))
+				}
+			}
 
 			// When verbose, show human-readable token expiration on stderr
 			// so it doesn't pollute the JSON output on stdout.
@@ -49,6 +82,10 @@ func decodeCmd(imsConfig *ims.Config) *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&imsConfig.Token, "token", "t", "", "Token.")
+	cmd.Flags().BoolVar(&verify, "verify", false,
+		"Verify the token signature with the IMS public keys and check its validity period.")
+	cmd.Flags().StringVar(&imsConfig.JWKS, "jwks", "",
+		"JWKS file or URL with the public keys used by --verify, instead of the IMS keys endpoint.")
 
 	return cmd
 }
@@ -77,3 +114,40 @@ func printTokenExpiration(payload string) {
 			expTime.Format(time.RFC3339), expTime.Sub(now).Truncate(time.Second))
 	}
 }
+
+// verificationOutput is the JSON representation of the token verification.
+type verificationOutput struct {
+	Valid          bool     `json:"valid"`
+	SignatureValid bool     `json:"signature_valid"`
+	Algorithm      string   `json:"algorithm"`
+	KeyID          string   `json:"key_id,omitempty"`
+	KeySource      string   `json:"key_source"`
+	Expired        bool     `json:"expired"`
+	NotYetValid    bool     `json:"not_yet_valid"`
+	ExpiresAt      string   `json:"expires_at,omitempty"`
+	NotBefore      string   `json:"not_before,omitempty"`
+	IssuedAt       string   `json:"issued_at,omitempty"`
+	Errors         []string `json:"errors,omitempty"`
+}
+
+func newVerificationOutput(v *ims.TokenVerification) verificationOutput {
+	format := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	return verificationOutput{
+		Valid:          v.Valid,
+		SignatureValid: v.SignatureValid,
+		Algorithm:      v.Algorithm,
+		KeyID:          v.KeyID,
+		KeySource:      v.KeySource,
+		Expired:        v.Expired,
+		NotYetValid:    v.NotYetValid,
+		ExpiresAt:      format(v.ExpiresAt),
+		NotBefore:      format(v.NotBefore),
+		IssuedAt:       format(v.IssuedAt),
+		Errors:         v.Errors,
+	}
+}
@@ -13,6 +13,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/adobe/imscli/cmd/prettify"
 	"github.com/adobe/imscli/ims"
 	"github.com/spf13/cobra"
 )
@@ -30,8 +31,7 @@ func exchangeCmd(imsConfig *ims.Config) *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("error exchanging the access token: %w", err)
 			}
-			fmt.Println(resp.AccessToken)
-			return nil
+			return printToken(cmd, imsConfig, resp.AccessToken)
 		},
 	}
 
@@ -52,3 +52,11 @@ func exchangeCmd(imsConfig *ims.Config) *cobra.Command {
 
 	return cmd
 }
+
+// printToken prints an access token obtained from an exchange.
+func printToken(cmd *cobra.Command, imsConfig *ims.Config, token string) error {
+	data := struct {
+		AccessToken string `json:"access_token"`
+	}{token}
+	return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, token)
+}
@@ -0,0 +1,246 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/adobe/imscli/ims"
+	"github.com/spf13/cobra"
+)
+
+// ExitError makes imscli exit with the given code without printing any error,
+// used to propagate the exit code of the command run by exec.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// forwardedSignals are the signals received by imscli and forwarded to the
+// command run by exec.
+var forwardedSignals = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGHUP}
+
+// restartGracePeriod is how long a command stopped for a restart is given to
+// exit after SIGTERM, before it is killed.
+var restartGracePeriod = 10 * time.Second
+
+// execOptions holds the flags of the exec command.
+type execOptions struct {
+	flow           string
+	envName        string
+	headerTemplate string
+	headerFileEnv  string
+	restart        bool
+	restartBefore  time.Duration
+}
+
+func execProcessCmd(imsConfig *ims.Config) *cobra.Command {
+	var opts execOptions
+
+	cmd := &cobra.Command{
+		Use:   "exec [flags] -- <command> [args...]",
+		Short: "Run a command with a fresh access token.",
+		Long: `Negotiate an access token with one of the authorize flows and run a command with the token in an
+environment variable, so the token never appears in the shell history or the process arguments.
+
+The token can also be written to a file rendered from a Go template, e.g. an HTTP header for curl -H @file, whose path
+is passed in another environment variable. Signals are forwarded to the command and imscli exits with its exit code.
+With --restart, the command is stopped with SIGTERM, killed if it does not exit within 10 seconds, and restarted with a
+new token before the token expires.`,
+		Example: `  imscli exec --flow client -c <client-id> -p <secret> -s openid -- ./deploy.sh
+  imscli exec --flow client -c <client-id> -p <secret> -s openid \
+    --headerTemplate 'Authorization: Bearer {{.AccessToken}}' -- sh -c 'curl -H @$IMS_HEADER_FILE https://...'`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			if err := checkFlow(opts.flow); err != nil {
+				return err
+			}
+			if opts.restartBefore < 0 {
+				return fmt.Errorf("invalid --restartBefore %s, it must not be negative", opts.restartBefore)
+			}
+			var tmpl *template.Template
+			if opts.headerTemplate != "" {
+				var err error
+				tmpl, err = template.New("header").Option("missingkey=error").Parse(opts.headerTemplate)
+				if err != nil {
+					return fmt.Errorf("invalid header template: %w", err)
+				}
+			}
+			return runWithToken(cmd, *imsConfig, opts, tmpl, args)
+		},
+	}
+
+	// The flags after the command name belong to the command.
+	cmd.Flags().SetInterspersed(false)
+	cmd.Flags().StringVarP(&opts.envName, "env", "e", "IMS_ACCESS_TOKEN",
+		"Environment variable holding the access token.")
+	cmd.Flags().StringVar(&opts.headerTemplate, "headerTemplate", "",
+		"Go template rendered with the token (.AccessToken, .ExpiresAt) to a temporary file passed to the command.")
+	cmd.Flags().StringVar(&opts.headerFileEnv, "headerFileEnv", "IMS_HEADER_FILE",
+		"Environment variable holding the path of the file rendered from --headerTemplate.")
+	cmd.Flags().BoolVar(&opts.restart, "restart", false,
+		"Restart the command with a new token before the token expires.")
+	cmd.Flags().DurationVar(&opts.restartBefore, "restartBefore", time.Minute,
+		"How long before the token expiration the command is restarted.")
+
+	addFlowFlags(cmd, imsConfig, &opts.flow)
+
+	return cmd
+}
+
+// runWithToken runs the command until it exits by itself, restarting it with
+// a new token when requested.
+func runWithToken(cmd *cobra.Command, config ims.Config, opts execOptions, tmpl *template.Template, args []string) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, forwardedSignals...)
+	defer signal.Stop(signals)
+
+	for {
+		info, err := config.Authorize(opts.flow)
+		if err != nil {
+			return fmt.Errorf("error in %s authorization: %w", opts.flow, err)
+		}
+
+		var timer *time.Timer
+		var restart <-chan time.Time
+		if opts.restart {
+			wait := time.Until(info.ExpiresAt) - opts.restartBefore
+			switch {
+			case info.ExpiresAt.IsZero():
+				log.Println("Unable to find the token expiration, the command will not be restarted.")
+			case wait <= 0 && !config.NoCache:
+				// The cached token is too close to its expiration, get a new one.
+				config.NoCache = true
+				continue
+			case wait <= 0:
+				return fmt.Errorf("the token expires in %s, not more than --restartBefore %s, the command would be "+
+					"restarted right away", time.Until(info.ExpiresAt).Round(time.Second), opts.restartBefore)
+			default:
+				timer = time.NewTimer(wait)
+				restart = timer.C
+			}
+		}
+
+		restarting, err := runChild(cmd, opts, tmpl, info, args, signals, restart)
+		if timer != nil {
+			timer.Stop()
+		}
+		if !restarting {
+			return err
+		}
+		// The cached token is about to expire, force a new one.
+		config.NoCache = true
+	}
+}
+
+// runChild runs the command once with the given token. It reports whether
+// the command was stopped for a restart.
+func runChild(cmd *cobra.Command, opts execOptions, tmpl *template.Template, info ims.AuthorizationInfo,
+	args []string, signals <-chan os.Signal, restart <-chan time.Time) (bool, error) {
+
+	child := exec.Command(args[0], args[1:]...)
+	child.Stdin = cmd.InOrStdin()
+	child.Stdout = cmd.OutOrStdout()
+	child.Stderr = cmd.ErrOrStderr()
+	child.Env = append(os.Environ(), opts.envName+"="+info.AccessToken)
+
+	if tmpl != nil {
+		path, err := writeHeaderFile(tmpl, info)
+		if err != nil {
+			return false, err
+		}
+		defer func() { _ = os.Remove(path) }()
+		child.Env = append(child.Env, opts.headerFileEnv+"="+path)
+	}
+
+	if err := child.Start(); err != nil {
+		return false, fmt.Errorf("error running %s: %w", args[0], err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- child.Wait() }()
+
+	restarting := false
+	var kill <-chan time.Time
+	for {
+		select {
+		case sig := <-signals:
+			log.Printf("Forwarding signal %s to %s.", sig, args[0])
+			_ = child.Process.Signal(sig)
+		case <-restart:
+			log.Printf("The token expires at %s, restarting %s.", info.ExpiresAt.Format(time.RFC3339), args[0])
+			restarting = true
+			if err := child.Process.Signal(syscall.SIGTERM); err != nil {
+				_ = child.Process.Kill()
+			}
+			kill = time.After(restartGracePeriod)
+		case <-kill:
+			log.Printf("%s did not exit %s after SIGTERM, killing it.", args[0], restartGracePeriod)
+			_ = child.Process.Kill()
+		case err := <-done:
+			if restarting {
+				return true, nil
+			}
+			return false, exitStatus(args[0], err)
+		}
+	}
+}
+
+// writeHeaderFile renders the header template to a temporary file readable
+// only by the current user.
+func writeHeaderFile(tmpl *template.Template, info ims.AuthorizationInfo) (string, error) {
+	f, err := os.CreateTemp("", "imscli-header-*")
+	if err != nil {
+		return "", fmt.Errorf("error creating the header file: %w", err)
+	}
+	err = tmpl.Execute(f, info)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("error writing the header file: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// exitStatus converts the result of the command to the error returned by
+// imscli, propagating its exit code.
+func exitStatus(name string, err error) error {
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &exitErr):
+		code := exitErr.ExitCode()
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			// Terminated by a signal, reported as shells do.
+			code = 128 + int(status.Signal())
+		} else if code < 0 {
+			code = 1
+		}
+		return &ExitError{Code: code}
+	default:
+		return fmt.Errorf("error running %s: %w", name, err)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/adobe/imscli/ims"
+	"github.com/spf13/cobra"
+)
+
+// addFlowFlags adds the --flow flag and the parameters of all the authorize
+// subcommands, for the commands negotiating a token with any flow.
+func addFlowFlags(cmd *cobra.Command, imsConfig *ims.Config, flow *string) {
+	cmd.Flags().StringVar(flow, "flow", "client",
+		fmt.Sprintf("Authorization flow used to negotiate the token: %s.", strings.Join(ims.AuthorizationFlows, ", ")))
+	cmd.Flags().StringVarP(&imsConfig.ClientID, "clientID", "c", "", "IMS client ID.")
+	cmd.Flags().StringVarP(&imsConfig.ClientSecret, "clientSecret", "p", "", "IMS client secret.")
+	cmd.Flags().StringVarP(&imsConfig.Organization, "organization", "o", "", "IMS Organization.")
+	cmd.Flags().StringSliceVarP(&imsConfig.Scopes, "scopes", "s", []string{}, "Scopes to request.")
+	cmd.Flags().StringSliceVarP(&imsConfig.Metascopes, "metascopes", "m", []string{}, "Metascopes to request.")
+	cmd.Flags().StringVarP(&imsConfig.Account, "account", "A", "", "Technical Account ID.")
+	cmd.Flags().StringVarP(&imsConfig.PrivateKeyPath, "privateKey", "k", "",
+		"Private key file: PEM (PKCS #1, PKCS #8, optionally encrypted) or PKCS #12.")
+	cmd.Flags().StringVar(&imsConfig.PrivateKeyPassphrase, "privateKeyPassphrase", "",
+		"Passphrase of an encrypted private key or PKCS #12 bundle, prompted when missing.")
+	cmd.Flags().StringVarP(&imsConfig.AuthorizationCode, "authorizationCode", "x", "", "Permanent authorization code.")
+	cmd.Flags().BoolVarP(&imsConfig.PublicClient, "public", "b", false, "Public client, ignore secret.")
+	cmd.Flags().IntVarP(&imsConfig.Port, "port", "l", 8888, "Local port to be used by the OAuth Client.")
+	cmd.Flags().StringVar(&imsConfig.RedirectURI, "redirectURI", ims.DefaultImplicitRedirectURI,
+		"Redirect URI registered for the implicit flow.")
+	cmd.Flags().StringSliceVarP(&imsConfig.Resource, "resource", "r", nil,
+		"RFC 8707 resource indicator URI(s) for audience-restricted tokens.")
+}
+
+// checkFlow validates the value of the --flow flag.
+func checkFlow(flow string) error {
+	if !slices.Contains(ims.AuthorizationFlows, flow) {
+		return fmt.Errorf("unknown authorization flow %q, supported flows are %s", flow,
+			strings.Join(ims.AuthorizationFlows, ", "))
+	}
+	return nil
+}
@@ -12,14 +12,23 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/adobe/imscli/ims"
+	"github.com/adobe/imscli/mockims"
+	"github.com/spf13/cobra"
 )
 
 // ---------- helpers ----------
@@ -655,3 +664,875 @@ func TestEnvVar_AccessToken(t *testing.T) {
 		t.Errorf("Authorization = %q, want %q", got.Header.Get("Authorization"), want)
 	}
 }
+
+// ---------- 8. Token cache ----------
+
+func TestTokenCache_ReusesToken(t *testing.T) {
+	mock, _ := newMockIMS(t)
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		mock.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	empty := writeConfigFile(t, "")
+
+	args := []string{"authorize", "clientCredentials",
+		"--url", srv.URL, "--configFile", empty,
+		"--clientID", "cache-cid",
+		"--clientSecret", "sec",
+		"--scopes", "openid"}
+	for range 2 {
+		if _, _, err := execCmd(t, args...); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("IMS received %d requests, want 1", got)
+	}
+
+	if _, _, err := execCmd(t, append(args, "--noCache")...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("IMS received %d requests with --noCache, want 2", got)
+	}
+}
+
+// ---------- 9. Output formats ----------
+
+func TestOutput_Formats(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "text is the default",
+			args: []string{"refresh", "--clientID", "cid", "--clientSecret", "sec", "--refreshToken", "rt"},
+			want: "at\n",
+		},
+		{
+			name: "json",
+			args: []string{"refresh", "--clientID", "cid", "--clientSecret", "sec", "--refreshToken", "rt", "--output", "json"},
+			want: "{\n  \"access_token\": \"at\",\n  \"refresh_token\": \"rt\"\n}\n",
+		},
+		{
+			name: "env",
+			args: []string{"validate", "accessToken", "--clientID", "cid", "--accessToken", "tok", "-O", "env"},
+			want: "export IMS_RESPONSE_VALID='true'\nexport IMS_VALID='true'\n",
+		},
+		{
+			name: "template",
+			args: []string{"organizations", "--accessToken", "tok", "--output", "template={{range .}}{{.orgName}}{{end}}"},
+			want: "test-org\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _ := newMockIMS(t)
+			empty := writeConfigFile(t, "")
+			args := append([]string{"--url", srv.URL, "--configFile", empty}, tt.args...)
+			stdout, _, err := execCmd(t, args...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stdout != tt.want {
+				t.Errorf("stdout = %q, want %q", stdout, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutput_InvalidFormat(t *testing.T) {
+	empty := writeConfigFile(t, "")
+	_, _, err := execCmd(t, "decode", "--configFile", empty, "--token", "a.b.c", "--output", "xml")
+	if err == nil || !strings.Contains(err.Error(), "unknown output format") {
+		t.Errorf("error = %v, want unknown output format", err)
+	}
+}
+
+// ---------- 10. Contexts ----------
+
+func contextsConfig(url string) string {
+	return "url: http://invalid.example.com\n" +
+		"current-context: stage\n" +
+		"contexts:\n" +
+		"  stage:\n" +
+		"    url: " + url + "\n" +
+		"    clientID: stage-cid\n" +
+		"  prod:\n" +
+		"    url: " + url + "\n" +
+		"    clientID: prod-cid\n"
+}
+
+func TestContext_Precedence(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want string
+	}{
+		{name: "current context", want: "stage-cid"},
+		{name: "context flag", args: []string{"--context", "prod"}, want: "prod-cid"},
+		{name: "context env var", env: map[string]string{"IMS_CONTEXT": "prod"}, want: "prod-cid"},
+		{name: "env var overrides context", env: map[string]string{"IMS_CLIENTID": "env-cid"}, want: "env-cid"},
+		{name: "flag overrides context", args: []string{"--clientID", "flag-cid"}, want: "flag-cid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, rlog := newMockIMS(t)
+			cfg := writeConfigFile(t, contextsConfig(srv.URL))
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			args := append([]string{"validate", "accessToken", "--configFile", cfg, "--accessToken", "tok"}, tt.args...)
+			if _, _, err := execCmd(t, args...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := rlog.Form["client_id"]; got != tt.want {
+				t.Errorf("client_id = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContext_NotFound(t *testing.T) {
+	cfg := writeConfigFile(t, contextsConfig("http://invalid.example.com"))
+	_, _, err := execCmd(t, "validate", "accessToken", "--configFile", cfg, "--context", "dev",
+		"--clientID", "cid", "--accessToken", "tok")
+	if err == nil || !strings.Contains(err.Error(), `context "dev" not found`) {
+		t.Errorf("error = %v, want context not found", err)
+	}
+
+	// The context commands keep working with a dangling context.
+	if _, _, err := execCmd(t, "context", "list", "--configFile", cfg, "--context", "dev"); err != nil {
+		t.Errorf("context list: unexpected error: %v", err)
+	}
+}
+
+func TestContext_Commands(t *testing.T) {
+	srv, rlog := newMockIMS(t)
+	cfg := writeConfigFile(t, "# imscli settings\nurl: http://invalid.example.com\n")
+
+	_, _, err := execCmd(t, "context", "create", "local", "--configFile", cfg,
+		"--url", srv.URL, "--clientID", "local-cid", "--clientSecret", "secret", "--use")
+	if err != nil {
+		t.Fatalf("context create: unexpected error: %v", err)
+	}
+	if _, _, err := execCmd(t, "context", "create", "LOCAL", "--configFile", cfg, "--clientID", "x"); err == nil {
+		t.Error("context create: expected an error for an existing context")
+	}
+
+	stdout, _, err := execCmd(t, "context", "list", "--configFile", cfg)
+	if err != nil {
+		t.Fatalf("context list: unexpected error: %v", err)
+	}
+	if stdout != "* local\n" {
+		t.Errorf("context list = %q, want %q", stdout, "* local\n")
+	}
+
+	stdout, _, err = execCmd(t, "context", "show", "--configFile", cfg, "-O", "json")
+	if err != nil {
+		t.Fatalf("context show: unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, `"clientSecret": "`+maskedSecret+`"`) || strings.Contains(stdout, `"secret"`) {
+		t.Errorf("context show did not mask the client secret: %s", stdout)
+	}
+
+	if _, _, err := execCmd(t, "validate", "accessToken", "--configFile", cfg, "--accessToken", "tok"); err != nil {
+		t.Fatalf("validate: unexpected error: %v", err)
+	}
+	if got := rlog.Form["client_id"]; got != "local-cid" {
+		t.Errorf("client_id = %q, want %q", got, "local-cid")
+	}
+
+	if _, _, err := execCmd(t, "context", "delete", "local", "--configFile", cfg); err != nil {
+		t.Fatalf("context delete: unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "current-context") || strings.Contains(string(data), "local-cid") {
+		t.Errorf("context delete left the context in the file:\n%s", data)
+	}
+	if !strings.HasPrefix(string(data), "# imscli settings\n") {
+		t.Errorf("the comments of the configuration file were not preserved:\n%s", data)
+	}
+}
+
+// ---------- 11. Mock IMS service ----------
+
+func TestMockIMS_EndToEnd(t *testing.T) {
+	s, err := mockims.New(mockims.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	empty := writeConfigFile(t, "")
+	common := []string{"--configFile", empty, "--url", srv.URL, "--noCache"}
+
+	token, _, err := execCmd(t, append([]string{"authorize", "client", "--clientID", "cid", "--clientSecret", "sec",
+		"--scopes", "openid"}, common...)...)
+	if err != nil {
+		t.Fatalf("authorize client: unexpected error: %v", err)
+	}
+	token = strings.TrimSpace(token)
+
+	if _, _, err := execCmd(t, append([]string{"decode", "--token", token, "--verify"}, common...)...); err != nil {
+		t.Errorf("decode --verify: unexpected error: %v", err)
+	}
+
+	validate := append([]string{"validate", "accessToken", "--clientID", "cid", "--accessToken", token,
+		"-O", "template={{.valid}}"}, common...)
+	if stdout, _, err := execCmd(t, validate...); err != nil || stdout != "true\n" {
+		t.Errorf("validate: stdout = %q, err = %v, want true", stdout, err)
+	}
+	if _, _, err := execCmd(t, append([]string{"invalidate", "accessToken", "--clientID", "cid",
+		"--accessToken", token}, common...)...); err != nil {
+		t.Fatalf("invalidate: unexpected error: %v", err)
+	}
+	if _, _, err := execCmd(t, validate...); err == nil || !strings.Contains(err.Error(), "token invalidated") {
+		t.Errorf("validate after invalidation: error = %v, want token invalidated", err)
+	}
+}
+
+// ---------- 12. Exec ----------
+
+func TestExec_InjectsTokenAndExitCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test command needs a POSIX shell")
+	}
+	s, err := mockims.New(mockims.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	empty := writeConfigFile(t, "")
+
+	stdout, _, err := execCmd(t, "--configFile", empty, "--url", srv.URL, "--noCache",
+		"exec", "--flow", "client", "-c", "cid", "-p", "sec", "-s", "openid", "--env", "TOKEN",
+		"--headerTemplate", "Authorization: Bearer {{.AccessToken}}",
+		"--", "sh", "-c", `echo "$TOKEN"; cat "$IMS_HEADER_FILE"; exit 3`)
+
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Fatalf("error = %v, want exit status 3", err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 || lines[0] == "" || lines[1] != "Authorization: Bearer "+lines[0] {
+		t.Errorf("stdout = %q, want the token and the rendered header", stdout)
+	}
+	tokens := s.Tokens()
+	if len(tokens) != 1 || tokens[0].Value != lines[0] {
+		t.Errorf("the command did not receive the token issued by IMS")
+	}
+}
+
+func TestExec_RestartBeforeTokenLifetime(t *testing.T) {
+	s, err := mockims.New(mockims.Options{TokenLifetime: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	empty := writeConfigFile(t, "")
+
+	_, _, err = execCmd(t, "--configFile", empty, "--url", srv.URL, "--noCache",
+		"exec", "--flow", "client", "-c", "cid", "-p", "sec", "-s", "openid", "--restart", "--restartBefore", "5m",
+		"--", "true")
+	if err == nil || !strings.Contains(err.Error(), "not more than --restartBefore") {
+		t.Errorf("error = %v, want the token lifetime to be rejected", err)
+	}
+	// No new token is requested in a loop.
+	if got := len(s.Tokens()); got != 1 {
+		t.Errorf("%d tokens issued, want 1", got)
+	}
+
+	_, _, err = execCmd(t, "exec", "--flow", "client", "--restart", "--restartBefore", "-1m", "--", "true")
+	if err == nil || !strings.Contains(err.Error(), "must not be negative") {
+		t.Errorf("error = %v, want a negative duration error", err)
+	}
+}
+
+func TestExec_SignalExitCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test command needs a POSIX shell")
+	}
+	s, err := mockims.New(mockims.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	empty := writeConfigFile(t, "")
+
+	_, _, err = execCmd(t, "--configFile", empty, "--url", srv.URL, "--noCache",
+		"exec", "--flow", "client", "-c", "cid", "-p", "sec", "-s", "openid", "--", "sh", "-c", "kill -KILL $$")
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 128+9 {
+		t.Errorf("error = %v, want exit status 137", err)
+	}
+}
+
+func TestExec_RestartKillsIgnoringCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test command needs a POSIX shell")
+	}
+	defer func(d time.Duration) { restartGracePeriod = d }(restartGracePeriod)
+	restartGracePeriod = 100 * time.Millisecond
+
+	// The command tells when it ignores SIGTERM, before the restart.
+	ready, readyWriter := io.Pipe()
+	cmd := &cobra.Command{}
+	cmd.SetOut(readyWriter)
+	cmd.SetErr(io.Discard)
+	restart := make(chan time.Time, 1)
+	start := time.Now()
+	done := make(chan bool, 1)
+	go func() {
+		restarting, _ := runChild(cmd, execOptions{envName: "TOKEN"}, nil, ims.AuthorizationInfo{},
+			[]string{"sh", "-c", `trap "" TERM; echo ready; exec sleep 30`}, nil, restart)
+		_ = readyWriter.Close()
+		done <- restarting
+	}()
+	go func() {
+		if _, err := ready.Read(make([]byte, 16)); err == nil {
+			restart <- time.Now()
+		}
+		_, _ = io.Copy(io.Discard, ready)
+	}()
+
+	select {
+	case restarting := <-done:
+		if !restarting || time.Since(start) < restartGracePeriod {
+			t.Errorf("restarting = %v after %s, want the command killed after the grace period", restarting,
+				time.Since(start))
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("the command ignoring SIGTERM was not killed")
+	}
+}
+
+func TestExec_UnknownFlow(t *testing.T) {
+	_, _, err := execCmd(t, "exec", "--flow", "magic", "--", "true")
+	if err == nil || !strings.Contains(err.Error(), `unknown authorization flow "magic"`) {
+		t.Errorf("error = %v, want an unknown flow error", err)
+	}
+}
+
+// ---------- 13. Agent ----------
+
+func TestAgent_TokenGet(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the agent listens on a Unix socket")
+	}
+	s, err := mockims.New(mockims.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	empty := writeConfigFile(t, "")
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+
+	agent := RootCmd("test")
+	agent.SetOut(io.Discard)
+	agent.SetErr(io.Discard)
+	agent.SetArgs([]string{"agent", "--configFile", empty, "--url", srv.URL, "--noCache", "--socket", socket,
+		"--flow", "client", "-c", "cid", "-p", "sec", "-s", "openid"})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- agent.ExecuteContext(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("agent: unexpected error: %v", err)
+		}
+	})
+
+	var stdout string
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if stdout, _, err = execCmd(t, "token", "get", "--agent", "--socket", socket); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		t.Fatalf("token get --agent: unexpected error: %v", err)
+	}
+	tokens := s.Tokens()
+	if len(tokens) != 1 || strings.TrimSpace(stdout) != tokens[0].Value {
+		t.Errorf("stdout = %q, want the token negotiated by the agent", stdout)
+	}
+}
+
+// ---------- 14. Secret references ----------
+
+func TestSecretReferences(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test relies on Unix file permissions and a POSIX shell")
+	}
+	dir := t.TempDir()
+	private := filepath.Join(dir, "private")
+	if err := os.WriteFile(private, []byte("file-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	shared := filepath.Join(dir, "shared")
+	if err := os.WriteFile(shared, []byte("file-secret\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("MY_SECRET", "env-secret")
+
+	tests := []struct {
+		name    string
+		ref     string
+		stdin   string
+		want    string
+		wantErr string
+	}{
+		{name: "literal", ref: "literal-secret", want: "literal-secret"},
+		{name: "file", ref: "@file:" + private, want: "file-secret"},
+		{name: "file readable by others", ref: "@file:" + shared, wantErr: "accessible by other users"},
+		{name: "missing file", ref: "@file:" + filepath.Join(dir, "missing"), wantErr: "error reading secret file"},
+		{name: "env", ref: "env:MY_SECRET", want: "env-secret"},
+		{name: "unset env", ref: "env:MY_MISSING_SECRET", wantErr: "MY_MISSING_SECRET is not set"},
+		{name: "command", ref: "cmd:printf 'cmd-secret\\n'", want: "cmd-secret"},
+		{name: "failing command", ref: "cmd:exit 1", wantErr: "error running secret command"},
+		{name: "stdin", ref: "-", stdin: "stdin-secret\n", want: "stdin-secret"},
+		{name: "empty stdin", ref: "-", wantErr: "empty secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, rlog := newMockIMS(t)
+			empty := writeConfigFile(t, "")
+			cmd := RootCmd("test")
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetIn(strings.NewReader(tt.stdin))
+			cmd.SetArgs([]string{"invalidate", "serviceToken", "--url", srv.URL, "--configFile", empty,
+				"--clientID", "cid", "--serviceToken", "st", "--clientSecret", tt.ref})
+			err := cmd.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := rlog.capturedRequest.Form["client_secret"]; got != tt.want {
+				t.Errorf("client_secret = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretReferences_FromConfigFile(t *testing.T) {
+	srv, rlog := newMockIMS(t)
+	t.Setenv("MY_SECRET", "env-secret")
+	cfg := writeConfigFile(t, "clientSecret: env:MY_SECRET\n")
+	_, _, err := execCmd(t, "invalidate", "serviceToken", "--url", srv.URL, "--configFile", cfg,
+		"--clientID", "cid", "--serviceToken", "st")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := rlog.capturedRequest.Form["client_secret"]; got != "env-secret" {
+		t.Errorf("client_secret = %q, want env-secret", got)
+	}
+}
+
+func TestSecretReferences_KeptByContextCreate(t *testing.T) {
+	t.Setenv("MYSEC", "supersecret")
+	cfg := writeConfigFile(t, "")
+	if _, _, err := execCmd(t, "context", "create", "prod", "--configFile", cfg,
+		"-c", "cid", "--clientSecret", "env:MYSEC"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "env:MYSEC") || strings.Contains(string(data), "supersecret") {
+		t.Errorf("configuration file:\n%s\nwant the reference, not the secret", data)
+	}
+
+	// The reference is resolved when the context is used.
+	srv, rlog := newMockIMS(t)
+	_, _, err = execCmd(t, "invalidate", "serviceToken", "--url", srv.URL, "--configFile", cfg,
+		"--context", "prod", "--serviceToken", "st")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := rlog.capturedRequest.Form["client_secret"]; got != "supersecret" {
+		t.Errorf("client_secret = %q, want supersecret", got)
+	}
+
+	if _, _, err := execCmd(t, "context", "create", "other", "--configFile", cfg, "--clientSecret", "-"); err == nil ||
+		!strings.Contains(err.Error(), "cannot be read from stdin") {
+		t.Errorf("stdin reference: error = %v, want cannot be read from stdin", err)
+	}
+}
+
+func TestSecretReferences_OneStdin(t *testing.T) {
+	empty := writeConfigFile(t, "")
+	_, _, err := execCmd(t, "invalidate", "serviceToken", "--url", "http://localhost", "--configFile", empty,
+		"--clientID", "cid", "--serviceToken", "-", "--clientSecret", "-")
+	if err == nil || !strings.Contains(err.Error(), "only one parameter can be read from stdin") {
+		t.Errorf("error = %v, want a stdin conflict", err)
+	}
+}
+
+func TestSecretStore_KeyringReference(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("IMS_SECRETPASSPHRASE", "passphrase")
+	empty := writeConfigFile(t, "")
+
+	set := RootCmd("test")
+	set.SetOut(io.Discard)
+	set.SetErr(io.Discard)
+	set.SetIn(strings.NewReader("stored-secret\n"))
+	set.SetArgs([]string{"secret", "set", "my-client", "--secretStore", "file", "--configFile", empty})
+	if err := set.Execute(); err != nil {
+		t.Fatalf("secret set: unexpected error: %v", err)
+	}
+
+	stdout, _, err := execCmd(t, "secret", "list", "--secretStore", "file", "--configFile", empty)
+	if err != nil || stdout != "my-client\n" {
+		t.Errorf("secret list: stdout = %q, err = %v, want my-client", stdout, err)
+	}
+
+	srv, rlog := newMockIMS(t)
+	_, _, err = execCmd(t, "invalidate", "serviceToken", "--url", srv.URL, "--configFile", empty,
+		"--secretStore", "file", "--clientID", "cid", "--serviceToken", "st", "--clientSecret", "keyring:my-client")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := rlog.capturedRequest.Form["client_secret"]; got != "stored-secret" {
+		t.Errorf("client_secret = %q, want stored-secret", got)
+	}
+
+	t.Setenv("IMS_SECRETPASSPHRASE", "wrong")
+	if _, _, err := execCmd(t, "secret", "get", "my-client", "--secretStore", "file", "--configFile", empty); err == nil {
+		t.Error("secret get: expected an error with a wrong passphrase")
+	}
+}
+
+// ---------- 15. Keys ----------
+
+func TestKeys_GenerateAndInspect(t *testing.T) {
+	t.Setenv("IMS_PRIVATEKEYPASSPHRASE", "passphrase")
+	empty := writeConfigFile(t, "")
+	dir := t.TempDir()
+	keyPath, certPath := filepath.Join(dir, "private.key"), filepath.Join(dir, "certificate_pub.crt")
+
+	_, _, err := execCmd(t, "keys", "generate", "--configFile", empty, "--encrypt",
+		"--privateKey", keyPath, "--certificate", certPath, "--subject", "CN=test,O=Acme", "--days", "30")
+	if err != nil {
+		t.Fatalf("keys generate: unexpected error: %v", err)
+	}
+	if runtime.GOOS != "windows" {
+		if fi, err := os.Stat(keyPath); err != nil || fi.Mode().Perm() != 0o600 {
+			t.Errorf("private key mode = %v, err = %v, want 0600", fi.Mode().Perm(), err)
+		}
+	}
+	if _, _, err := execCmd(t, "keys", "generate", "--configFile", empty,
+		"--privateKey", keyPath, "--certificate", certPath); err == nil {
+		t.Error("keys generate: expected an error when the files exist")
+	}
+
+	stdout, _, err := execCmd(t, "keys", "inspect", "--configFile", empty, "-O", "json",
+		"--privateKey", keyPath, "--certificate", certPath)
+	if err != nil {
+		t.Fatalf("keys inspect: unexpected error: %v", err)
+	}
+	for _, want := range []string{`"encrypted": true`, `"subject": "CN=test,O=Acme"`, `"match": true`} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("keys inspect output does not contain %s:\n%s", want, stdout)
+		}
+	}
+
+	t.Setenv("IMS_PRIVATEKEYPASSPHRASE", "wrong")
+	_, _, err = execCmd(t, "keys", "inspect", "--configFile", empty, "--privateKey", keyPath)
+	if err == nil || !strings.Contains(err.Error(), "incorrect passphrase") {
+		t.Errorf("keys inspect: error = %v, want an incorrect passphrase", err)
+	}
+}
+
+func TestKeys_GenerateEncryptedPKCS1(t *testing.T) {
+	empty := writeConfigFile(t, "")
+	dir := t.TempDir()
+	// Rejected before a passphrase is asked for, none is set.
+	_, _, err := execCmd(t, "keys", "generate", "--configFile", empty, "--encrypt", "--format", "pkcs1",
+		"--privateKey", filepath.Join(dir, "private.key"), "--certificate", filepath.Join(dir, "certificate_pub.crt"))
+	if err == nil || !strings.Contains(err.Error(), "only pkcs8 keys can be encrypted") {
+		t.Errorf("error = %v, want only pkcs8 keys can be encrypted", err)
+	}
+}
+
+// ---------- 16. Batch ----------
+
+func TestBatch_InvalidateFromFile(t *testing.T) {
+	s, err := mockims.New(mockims.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	empty := writeConfigFile(t, "")
+	common := []string{"--configFile", empty, "--url", srv.URL, "--noCache", "--clientID", "cid"}
+
+	var tokens []string
+	for range 3 {
+		token, _, err := execCmd(t, append([]string{"authorize", "client", "--clientSecret", "sec",
+			"--scopes", "openid"}, common...)...)
+		if err != nil {
+			t.Fatalf("authorize client: unexpected error: %v", err)
+		}
+		tokens = append(tokens, strings.TrimSpace(token))
+	}
+	file := filepath.Join(t.TempDir(), "tokens.txt")
+	content := tokens[0] + "\n" + tokens[1] + "\n" + `{"token":"` + tokens[2] + `","type":"access_token"}` + "\n"
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _, err := execCmd(t, append([]string{"invalidate", "accessToken", "--fromFile", file, "-O", "json"},
+		common...)...)
+	if err != nil {
+		t.Fatalf("invalidate --fromFile: unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, `"failed": 0`) || strings.Contains(stdout, tokens[0]) {
+		t.Errorf("invalidate --fromFile output:\n%s", stdout)
+	}
+
+	stdout, _, err = execCmd(t, append([]string{"validate", "accessToken", "--fromFile", file}, common...)...)
+	if err == nil || !strings.Contains(err.Error(), "3 of 3 tokens failed validation") {
+		t.Errorf("validate --fromFile: error = %v, want 3 failed tokens", err)
+	}
+	if !strings.Contains(stdout, "0 of 3 tokens valid.") {
+		t.Errorf("validate --fromFile output:\n%s", stdout)
+	}
+
+	_, _, err = execCmd(t, append([]string{"validate", "accessToken", "--fromFile", file, "--accessToken", "x"},
+		common...)...)
+	if err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Errorf("error = %v, want a flag conflict", err)
+	}
+}
+
+// ---------- 17. DCR ----------
+
+func TestDCR_Lifecycle(t *testing.T) {
+	s, err := mockims.New(mockims.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	empty := writeConfigFile(t, "")
+	common := []string{"--configFile", empty, "--url", srv.URL}
+
+	stdout, _, err := execCmd(t, append([]string{"dcr", "register", "--clientName", "app",
+		"--redirectURIs", "https://example.com/cb", "-O", "template={{.client_id}} {{.registration_access_token}}"},
+		common...)...)
+	if err != nil {
+		t.Fatalf("dcr register: unexpected error: %v", err)
+	}
+	client := strings.Fields(stdout)
+	if len(client) != 2 {
+		t.Fatalf("dcr register: stdout = %q, want the client ID and the registration access token", stdout)
+	}
+	manage := append([]string{"--clientID", client[0], "--registrationAccessToken", client[1]}, common...)
+
+	stdout, _, err = execCmd(t, append([]string{"dcr", "update", "--clientName", "renamed",
+		"-O", "template={{.client_name}}"}, manage...)...)
+	if err != nil || stdout != "renamed\n" {
+		t.Errorf("dcr update: stdout = %q, err = %v, want renamed", stdout, err)
+	}
+	if _, _, err := execCmd(t, append([]string{"dcr", "delete"}, manage...)...); err != nil {
+		t.Errorf("dcr delete: unexpected error: %v", err)
+	}
+	if _, _, err := execCmd(t, append([]string{"dcr", "get"}, manage...)...); err == nil {
+		t.Error("dcr get after deletion: expected an error")
+	}
+}
+
+func TestDCR_RegisterSave(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("IMS_SECRETPASSPHRASE", "passphrase")
+	s, err := mockims.New(mockims.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	configFile := writeConfigFile(t, "")
+
+	register := []string{"dcr", "register", "--configFile", configFile, "--url", srv.URL, "--clientName", "app",
+		"--redirectURIs", "http://localhost:9999/callback", "--scopes", "openid,profile", "--save", "app", "--use",
+		"--secretStore", "file", "-O", "template={{.client_secret}}"}
+	secret, _, err := execCmd(t, register...)
+	if err != nil {
+		t.Fatalf("dcr register --save: unexpected error: %v", err)
+	}
+	if _, _, err := execCmd(t, register...); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("dcr register --save twice: error = %v, want an existing context", err)
+	}
+
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"current-context: app", "clientSecret: keyring:app-clientSecret",
+		"registrationAccessToken: keyring:app-registrationAccessToken", "port: 9999", "- profile"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("configuration file does not contain %q:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), strings.TrimSpace(secret)) {
+		t.Errorf("configuration file contains the client secret:\n%s", data)
+	}
+
+	// The saved context is enough to use the client, whose secret is enforced by the mock.
+	if _, _, err := execCmd(t, "authorize", "client", "--configFile", configFile, "--secretStore", "file",
+		"--noCache"); err != nil {
+		t.Errorf("authorize client with the saved context: unexpected error: %v", err)
+	}
+	stdout, _, err := execCmd(t, "dcr", "get", "--configFile", configFile, "--secretStore", "file",
+		"-O", "template={{.client_name}}")
+	if err != nil || stdout != "app\n" {
+		t.Errorf("dcr get with the saved context: stdout = %q, err = %v, want app", stdout, err)
+	}
+}
+
+func TestDCR_RegisterSavePlaintext(t *testing.T) {
+	s, err := mockims.New(mockims.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	configFile := writeConfigFile(t, "")
+
+	secret, stderr, err := execCmd(t, "dcr", "register", "--configFile", configFile, "--url", srv.URL,
+		"--clientName", "app", "--redirectURIs", "https://example.com/cb", "--save", "app", "--storeSecrets=false",
+		"-O", "template={{.client_secret}}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stderr, "Warning: clientSecret and registrationAccessToken of context app written in plaintext") {
+		t.Errorf("stderr = %q, want a plaintext warning", stderr)
+	}
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "clientSecret: "+strings.TrimSpace(secret)) {
+		t.Errorf("configuration file:\n%s\nwant the client secret", data)
+	}
+}
+
+func TestDCR_RegisterMetadata(t *testing.T) {
+	s, err := mockims.New(mockims.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	empty := writeConfigFile(t, "")
+	metadata := filepath.Join(t.TempDir(), "metadata.json")
+	err = os.WriteFile(metadata, []byte(`{"client_name": "service", "grant_types": ["client_credentials"],
+		"token_endpoint_auth_method": "client_secret_post", "contacts": ["ops@example.com"]}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _, err := execCmd(t, "dcr", "register", "--configFile", empty, "--url", srv.URL, "--metadata", metadata,
+		"--logoURI", "https://example.com/logo.png", "-O", "json")
+	if err != nil {
+		t.Fatalf("dcr register --metadata: unexpected error: %v", err)
+	}
+	for _, want := range []string{`"client_credentials"`, `"client_secret_post"`, `"ops@example.com"`,
+		`"logo_uri": "https://example.com/logo.png"`} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("dcr register output does not contain %s:\n%s", want, stdout)
+		}
+	}
+
+	_, _, err = execCmd(t, "dcr", "register", "--configFile", empty, "--url", srv.URL, "--metadata", metadata,
+		"--grantTypes", "magic")
+	if err == nil || !strings.Contains(err.Error(), `invalid grant type "magic"`) {
+		t.Errorf("error = %v, want an invalid grant type", err)
+	}
+}
+
+// ---------- 18. Profile selection ----------
+
+func TestProfile_Selection(t *testing.T) {
+	s, err := mockims.New(mockims.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	empty := writeConfigFile(t, "")
+	common := []string{"--configFile", empty, "--url", srv.URL, "--noCache"}
+
+	token, _, err := execCmd(t, append([]string{"authorize", "client", "--clientID", "cid", "--clientSecret", "sec",
+		"--scopes", "openid"}, common...)...)
+	if err != nil {
+		t.Fatalf("authorize client: unexpected error: %v", err)
+	}
+	profile := append([]string{"profile", "--accessToken", strings.TrimSpace(token)}, common...)
+
+	stdout, _, err := execCmd(t, append(profile, "-O", "json")...)
+	var typed ims.Profile
+	if err != nil || json.Unmarshal([]byte(stdout), &typed) != nil || typed.UserID == "" || len(typed.Roles) != 1 {
+		t.Errorf("-O json: stdout = %q, err = %v, want the typed profile", stdout, err)
+	}
+	stdout, _, err = execCmd(t, append(profile, "--fields", "email,roles", "-O", "template={{.email}}")...)
+	if err != nil || stdout != "mock.user@example.com\n" {
+		t.Errorf("--fields: stdout = %q, err = %v, want the email", stdout, err)
+	}
+	stdout, _, err = execCmd(t, append(profile, "--query", "$.roles[?(@.target_type=='TRG_ORG')].named_role")...)
+	if err != nil || !strings.Contains(stdout, `"user"`) {
+		t.Errorf("--query: stdout = %q, err = %v, want the named roles", stdout, err)
+	}
+	stdout, _, err = execCmd(t, append(profile, "--query", "email")...)
+	if err != nil || stdout != "mock.user@example.com\n" {
+		t.Errorf("--query email: stdout = %q, err = %v, want the email", stdout, err)
+	}
+	if _, _, err := execCmd(t, append(profile, "--fields", "email", "--query", "email")...); err == nil {
+		t.Error("--fields and --query: expected an error")
+	}
+	if _, _, err := execCmd(t, append(profile, "--fields", "missing")...); err == nil ||
+		!strings.Contains(err.Error(), `field "missing" not found`) {
+		t.Errorf("--fields missing: error = %v, want field not found", err)
+	}
+}
+
+func TestProfile_FulfillableDataDecoders(t *testing.T) {
+	srv, _ := newMockIMS(t)
+	cfg := writeConfigFile(t, "fulfillableDataDecoders:\n  my_service: zip\n")
+	_, _, err := execCmd(t, "profile", "--url", srv.URL, "--configFile", cfg, "--accessToken", "tok",
+		"--decodeFulfillableData")
+	if err == nil || !strings.Contains(err.Error(), `invalid fulfillable data encoding "zip"`) {
+		t.Errorf("config file: error = %v, want invalid encoding", err)
+	}
+
+	empty := writeConfigFile(t, "")
+	_, _, err = execCmd(t, "profile", "--url", srv.URL, "--configFile", empty, "--accessToken", "tok",
+		"--decodeFulfillableData", "--fulfillableDataDecoders", "my_service=base64,other=auto")
+	if err != nil {
+		t.Errorf("flag: unexpected error: %v", err)
+	}
+	_, _, err = execCmd(t, "profile", "--url", srv.URL, "--configFile", empty, "--accessToken", "tok",
+		"--fulfillableDataDecoders", "my_service=lz4")
+	if err == nil || !strings.Contains(err.Error(), `"lz4"`) {
+		t.Errorf("flag: error = %v, want invalid encoding", err)
+	}
+}
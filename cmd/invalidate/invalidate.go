@@ -14,10 +14,22 @@ package invalidate
 import (
 	"fmt"
 
+	"github.com/adobe/imscli/cmd/batch"
+	"github.com/adobe/imscli/cmd/prettify"
 	"github.com/adobe/imscli/ims"
 	"github.com/spf13/cobra"
 )
 
+// operation is the batch mode of the invalidate subcommands.
+var operation = batch.Operation{
+	Verb:       "invalidate",
+	Participle: "invalidated",
+	Noun:       "invalidation",
+	Done:       "invalidated",
+	Failure:    "cannot be invalidated",
+	Run:        ims.Config.InvalidateTokens,
+}
+
 type tokenDef struct {
 	use        string
 	alias      string
@@ -29,25 +41,35 @@ type tokenDef struct {
 }
 
 func tokenCmd(imsConfig *ims.Config, def tokenDef) *cobra.Command {
+	var batchMode *batch.Mode
+
 	cmd := &cobra.Command{
 		Use:     def.use,
 		Aliases: []string{def.alias},
 		Short:   fmt.Sprintf("Invalidate %s.", def.label),
-		Long:    fmt.Sprintf("Invalidate %s.", def.label),
+		Long:    fmt.Sprintf("Invalidate %s.\n\n%s", def.label, operation.Help()),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.SilenceUsage = true
 
+			if batchMode.Enabled() {
+				return batchMode.Run(cmd, *imsConfig, def.use, def.flagName)
+			}
+
 			err := imsConfig.InvalidateToken()
 			if err != nil {
 				return fmt.Errorf("error invalidating the %s: %w", def.label, err)
 			}
-			fmt.Println(def.successMsg)
-			return nil
+			data := struct {
+				Invalidated bool   `json:"invalidated"`
+				TokenType   string `json:"token_type"`
+			}{true, def.use}
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, def.successMsg)
 		},
 	}
 
 	cmd.Flags().StringVarP(def.field, def.flagName, "t", "", def.label+".")
 	cmd.Flags().StringVarP(&imsConfig.ClientID, "clientID", "c", "", "IMS Client ID.")
+	batchMode = batch.AddFlags(cmd, operation)
 	if def.extraFlags != nil {
 		def.extraFlags(cmd, imsConfig)
 	}
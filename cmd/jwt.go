@@ -0,0 +1,87 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/adobe/imscli/cmd/prettify"
+	"github.com/adobe/imscli/ims"
+	"github.com/spf13/cobra"
+)
+
+func jwtCmd(imsConfig *ims.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jwt",
+		Short: "Work with the JWT assertions of the JWT Bearer flow.",
+		Long: `The jwt command builds the assertions exchanged by "authorize jwt" locally, to debug the errors returned by IMS.
+
+This command has no effect by itself, the operation needs to be specified as a subcommand.
+`,
+	}
+	cmd.AddCommand(jwtSignCmd(imsConfig))
+	return cmd
+}
+
+func jwtSignCmd(imsConfig *ims.Config) *cobra.Command {
+	var decode bool
+
+	cmd := &cobra.Command{
+		Use:   "sign",
+		Short: "Sign a JWT assertion without exchanging it.",
+		Long: "Build and sign the assertion sent by 'authorize jwt': the organization as issuer, the technical account " +
+			"as subject, the audience derived from the IMS URL and the client ID, the expiration and the metascope " +
+			"claims, signed with the private key. With --decode, the header and payload are printed as well.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			assertion, err := imsConfig.SignJWT()
+			if err != nil {
+				return fmt.Errorf("error signing the JWT: %w", err)
+			}
+			if !decode {
+				data := struct {
+					Assertion string `json:"assertion"`
+				}{assertion}
+				return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, assertion)
+			}
+
+			decoded, err := ims.Config{Token: assertion}.DecodeToken()
+			if err != nil {
+				return fmt.Errorf("error decoding the JWT: %w", err)
+			}
+			data := struct {
+				Assertion string `json:"assertion"`
+				Header    any    `json:"header"`
+				Payload   any    `json:"payload"`
+			}{assertion, prettify.RawJSON(decoded.Header), prettify.RawJSON(decoded.Payload)}
+			b, err := json.Marshal(data)
+			if err != nil {
+				return fmt.Errorf("error encoding the JWT: %w", err)
+			}
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, prettify.JSON(string(b)))
+		},
+	}
+
+	cmd.Flags().StringVarP(&imsConfig.ClientID, "clientID", "c", "", "IMS Client ID.")
+	cmd.Flags().StringVarP(&imsConfig.Organization, "organization", "o", "", "IMS Organization.")
+	cmd.Flags().StringVarP(&imsConfig.Account, "account", "A", "", "Technical Account ID.")
+	cmd.Flags().StringVarP(&imsConfig.PrivateKeyPath, "privateKey", "k", "",
+		"Private key file: PEM (PKCS #1, PKCS #8, optionally encrypted) or PKCS #12.")
+	cmd.Flags().StringVar(&imsConfig.PrivateKeyPassphrase, "privateKeyPassphrase", "",
+		"Passphrase of an encrypted private key or PKCS #12 bundle, prompted when missing.")
+	cmd.Flags().StringSliceVarP(&imsConfig.Metascopes, "metascopes", "m", []string{}, "Metascopes to request.")
+	cmd.Flags().BoolVarP(&decode, "decode", "d", false, "Print the decoded header and payload with the assertion.")
+
+	return cmd
+}
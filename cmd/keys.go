@@ -0,0 +1,299 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adobe/imscli/cmd/prettify"
+	"github.com/adobe/imscli/ims"
+	"github.com/adobe/imscli/keys"
+	"github.com/spf13/cobra"
+)
+
+func keysCmd(imsConfig *ims.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Generate and inspect the keys of JWT service accounts.",
+		Long: `The keys command generates the RSA key pair and the self-signed certificate of a JWT service account, and
+inspects existing keys and certificates. The certificate is uploaded to the developer console and the private key is
+used as the --privateKey parameter of "authorize jwt".
+
+This command has no effect by itself, the operation needs to be specified as a subcommand.
+`,
+	}
+	cmd.AddCommand(
+		keysGenerateCmd(imsConfig),
+		keysInspectCmd(imsConfig),
+	)
+	return cmd
+}
+
+func keysGenerateCmd(imsConfig *ims.Config) *cobra.Command {
+	var (
+		keyPath, certPath, format, subject string
+		bits, days                         int
+		encrypt, force                     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a private key and a self-signed certificate.",
+		Long: "Generate an RSA private key and a matching self-signed certificate. The key is written as PKCS #8 or " +
+			"PKCS #1 and, with --encrypt, encrypted with a passphrase read from --privateKeyPassphrase or prompted on " +
+			"the terminal. Existing files are only overwritten with --force.",
+		Example: `  imscli keys generate --subject "CN=my-integration,O=My Company,C=US" --days 730`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			name, err := keys.ParseSubject(subject)
+			if err != nil {
+				return fmt.Errorf("invalid subject: %w", err)
+			}
+			if days <= 0 {
+				return fmt.Errorf("the validity must be at least one day")
+			}
+			// Checked before the passphrase is prompted.
+			switch {
+			case format != keys.FormatPKCS1 && format != keys.FormatPKCS8:
+				return fmt.Errorf("unknown private key format %q, use %s or %s", format, keys.FormatPKCS1, keys.FormatPKCS8)
+			case encrypt && format != keys.FormatPKCS8:
+				return fmt.Errorf("only %s keys can be encrypted, use --format %s", keys.FormatPKCS8, keys.FormatPKCS8)
+			}
+			if !force {
+				// Check both files first, not to leave a key without its certificate.
+				for _, path := range []string{keyPath, certPath} {
+					if _, err := os.Stat(path); err == nil {
+						return fmt.Errorf("%s already exists, use --force to overwrite it", path)
+					}
+				}
+			}
+			var passphrase string
+			if encrypt {
+				if passphrase, err = newKeyPassphrase(cmd, imsConfig.PrivateKeyPassphrase); err != nil {
+					return err
+				}
+			}
+
+			keyPEM, certPEM, err := keys.Generate(keys.GenerateOptions{
+				Bits:       bits,
+				Format:     format,
+				Passphrase: passphrase,
+				Subject:    name,
+				Validity:   time.Duration(days) * 24 * time.Hour,
+			})
+			if err != nil {
+				return fmt.Errorf("error generating the key pair: %w", err)
+			}
+			defer clear(keyPEM)
+			cert, err := keys.InspectCertificate(certPEM)
+			if err != nil {
+				return err
+			}
+
+			if err := writeNewFile(keyPath, keyPEM, 0o600, force); err != nil {
+				return err
+			}
+			if err := writeNewFile(certPath, certPEM, 0o644, force); err != nil {
+				return err
+			}
+
+			data := struct {
+				PrivateKey  string    `json:"private_key"`
+				Certificate string    `json:"certificate"`
+				Fingerprint string    `json:"fingerprint"`
+				NotAfter    time.Time `json:"not_after"`
+			}{keyPath, certPath, cert.Fingerprint, cert.NotAfter}
+			text := fmt.Sprintf("Private key written to %s.\nCertificate written to %s, valid until %s.\nSHA-256 fingerprint: %s",
+				keyPath, certPath, cert.NotAfter.Format(time.RFC3339), cert.Fingerprint)
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, text)
+		},
+	}
+
+	cmd.Flags().StringVarP(&keyPath, "privateKey", "k", "private.key", "Private key file to write.")
+	cmd.Flags().StringVar(&certPath, "certificate", "certificate_pub.crt", "Certificate file to write.")
+	cmd.Flags().StringVar(&format, "format", keys.FormatPKCS8,
+		fmt.Sprintf("Private key format: %s or %s.", keys.FormatPKCS8, keys.FormatPKCS1))
+	cmd.Flags().IntVar(&bits, "bits", 2048, "RSA key size in bits.")
+	cmd.Flags().StringVar(&subject, "subject", "CN=imscli",
+		"Subject of the certificate, e.g. \"CN=name,O=Org,C=US\".")
+	cmd.Flags().IntVar(&days, "days", 365, "Validity of the certificate in days.")
+	cmd.Flags().BoolVar(&encrypt, "encrypt", false, "Encrypt the private key with a passphrase (PKCS #8 only).")
+	cmd.Flags().StringVar(&imsConfig.PrivateKeyPassphrase, "privateKeyPassphrase", "",
+		"Passphrase of the private key, prompted when missing.")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing files.")
+
+	return cmd
+}
+
+func keysInspectCmd(imsConfig *ims.Config) *cobra.Command {
+	var keyPath, certPath string
+
+	cmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Inspect a private key and a certificate.",
+		Long: "Print the size, format and public key fingerprint of a private key, and the subject, validity and " +
+			"fingerprints of a certificate. When both are given, tell whether they belong to the same key pair.",
+		Example: "  imscli keys inspect --privateKey private.key --certificate certificate_pub.crt",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			if keyPath == "" && certPath == "" {
+				return fmt.Errorf("missing private key or certificate parameter")
+			}
+
+			var data struct {
+				PrivateKey  *keys.KeyInfo         `json:"private_key,omitempty"`
+				Certificate *keys.CertificateInfo `json:"certificate,omitempty"`
+				Match       *bool                 `json:"match,omitempty"`
+			}
+			var text []string
+			if keyPath != "" {
+				b, err := os.ReadFile(keyPath)
+				if err != nil {
+					return fmt.Errorf("error reading private key file %s: %w", keyPath, err)
+				}
+				info, err := keys.InspectKey(b, keyPassphrase(cmd, imsConfig.PrivateKeyPassphrase))
+				clear(b)
+				if err != nil {
+					return fmt.Errorf("error reading private key file %s: %w", keyPath, err)
+				}
+				data.PrivateKey = &info
+				text = append(text, formatKeyInfo(info))
+			}
+			if certPath != "" {
+				b, err := os.ReadFile(certPath)
+				if err != nil {
+					return fmt.Errorf("error reading certificate file %s: %w", certPath, err)
+				}
+				info, err := keys.InspectCertificate(b)
+				if err != nil {
+					return fmt.Errorf("error reading certificate file %s: %w", certPath, err)
+				}
+				data.Certificate = &info
+				text = append(text, formatCertificateInfo(info))
+			}
+			if data.PrivateKey != nil && data.Certificate != nil {
+				match := data.PrivateKey.Fingerprint == data.Certificate.PublicKeyFingerprint
+				data.Match = &match
+				if match {
+					text = append(text, "The private key matches the certificate.")
+				} else {
+					text = append(text, "The private key does NOT match the certificate.")
+				}
+			}
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, strings.Join(text, "\n\n"))
+		},
+	}
+
+	cmd.Flags().StringVarP(&keyPath, "privateKey", "k", "", "Private key file.")
+	cmd.Flags().StringVar(&certPath, "certificate", "", "Certificate file.")
+	cmd.Flags().StringVar(&imsConfig.PrivateKeyPassphrase, "privateKeyPassphrase", "",
+		"Passphrase of the private key, prompted when the key is encrypted.")
+
+	return cmd
+}
+
+// keyPassphrase returns the passphrase of an encrypted private key, prompting
+// for it when it is not configured.
+func keyPassphrase(cmd *cobra.Command, passphrase string) keys.PassphraseFunc {
+	return func() (string, error) {
+		if passphrase != "" {
+			return passphrase, nil
+		}
+		p, err := promptSecret(cmd, "Private key passphrase: ")
+		if errors.Is(err, errNoTerminal) {
+			return "", fmt.Errorf("%w, set IMS_PRIVATEKEYPASSPHRASE or run in a terminal", keys.ErrPassphraseRequired)
+		}
+		return p, err
+	}
+}
+
+// newKeyPassphrase returns the passphrase to encrypt a new private key,
+// prompting for it twice when it is not configured.
+func newKeyPassphrase(cmd *cobra.Command, passphrase string) (string, error) {
+	if passphrase != "" {
+		return passphrase, nil
+	}
+	p, err := promptSecret(cmd, "New private key passphrase: ")
+	if errors.Is(err, errNoTerminal) {
+		return "", fmt.Errorf("missing private key passphrase, set IMS_PRIVATEKEYPASSPHRASE or run in a terminal")
+	}
+	if err != nil {
+		return "", err
+	}
+	confirm, err := promptSecret(cmd, "Confirm the passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case p == "":
+		return "", fmt.Errorf("empty passphrase")
+	case p != confirm:
+		return "", fmt.Errorf("the passphrases do not match")
+	}
+	return p, nil
+}
+
+// writeNewFile writes a file with the given permissions, refusing to replace
+// an existing file unless forced.
+func writeNewFile(path string, data []byte, perm os.FileMode, force bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, perm)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s already exists, use --force to overwrite it", path)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return nil
+}
+
+func formatKeyInfo(info keys.KeyInfo) string {
+	encrypted := ""
+	if info.Encrypted {
+		encrypted = ", encrypted"
+	}
+	return fmt.Sprintf("Private key: %s %d bits, %s%s\nPublic key SHA-256: %s",
+		info.Algorithm, info.Bits, strings.ToUpper(info.Format), encrypted, info.Fingerprint)
+}
+
+func formatCertificateInfo(info keys.CertificateInfo) string {
+	validity := fmt.Sprintf("expires in %d days", int(time.Until(info.NotAfter).Hours()/24))
+	if info.Expired {
+		validity = "EXPIRED"
+	}
+	selfSigned := ""
+	if info.SelfSigned {
+		selfSigned = " (self-signed)"
+	}
+	return fmt.Sprintf("Certificate: %s\nIssuer: %s%s\nSerial number: %s\nValidity: %s to %s, %s\n"+
+		"SHA-256 fingerprint: %s\nPublic key SHA-256: %s",
+		info.Subject, info.Issuer, selfSigned, info.SerialNumber,
+		info.NotBefore.Format(time.RFC3339), info.NotAfter.Format(time.RFC3339), validity,
+		info.Fingerprint, info.PublicKeyFingerprint)
+}
@@ -0,0 +1,35 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+// TestMain points the user configuration directory at a temporary directory,
+// so the tests neither read the developer's imscli configuration nor write to
+// the real token cache.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "imscli-test")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to create temporary config dir: %v\n", err)
+		os.Exit(1)
+	}
+	for _, env := range []string{"HOME", "XDG_CONFIG_HOME", "AppData"} {
+		_ = os.Setenv(env, dir)
+	}
+
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
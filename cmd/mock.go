@@ -0,0 +1,32 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package cmd
+
+import (
+	"github.com/adobe/imscli/cmd/mock"
+	"github.com/spf13/cobra"
+)
+
+func mockCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mock",
+		Short: "Run a mock IMS service.",
+		Long: `The mock command runs a fake IMS service for local development and testing, so imscli and the services
+using IMS can run fully offline.
+
+This command has no effect by itself, the operation needs to be specified as a subcommand.
+`,
+	}
+	cmd.AddCommand(
+		mock.ServeCmd(),
+	)
+	return cmd
+}
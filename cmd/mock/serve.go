@@ -0,0 +1,91 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+// Package mock implements the mock subcommands (serve).
+package mock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/adobe/imscli/mockims"
+	"github.com/spf13/cobra"
+)
+
+func ServeCmd() *cobra.Command {
+	var address string
+	var opts mockims.Options
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the mock IMS service.",
+		Long: "Serve a mock IMS service implementing the endpoints used by imscli: token, validate_token, " +
+			"invalidate_token, profile, organizations, admin profile and organizations, register, authorize with a " +
+			"fake login page, device authorization and the JWKS keys endpoint. Tokens are real JWTs signed with a key " +
+			"generated at startup. The service runs until interrupted.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			server, err := mockims.New(opts)
+			if err != nil {
+				return fmt.Errorf("error creating the mock IMS service: %w", err)
+			}
+			serviceCode, err := server.IssueServiceCode(nil)
+			if err != nil {
+				return fmt.Errorf("error creating the mock IMS service: %w", err)
+			}
+
+			listener, err := net.Listen("tcp", address)
+			if err != nil {
+				return fmt.Errorf("unable to listen at %s: %w", address, err)
+			}
+			url := "http://" + listener.Addr().String()
+			fmt.Fprintf(cmd.ErrOrStderr(), "Mock IMS listening at %s, use it with --url %s\n", url, url)
+			fmt.Fprintf(cmd.ErrOrStderr(), "Authorization code for the service flow: %s\n", serviceCode)
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			srv := &http.Server{Handler: server.Handler(), ReadHeaderTimeout: 10 * time.Second}
+			errCh := make(chan error, 1)
+			go func() { errCh <- srv.Serve(listener) }()
+
+			select {
+			case err := <-errCh:
+				return fmt.Errorf("error serving the mock IMS service: %w", err)
+			case <-ctx.Done():
+			}
+
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("error stopping the mock IMS service: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&address, "address", "a", "localhost:8081", "Address to listen at.")
+	cmd.Flags().BoolVar(&opts.AutoLogin, "autoLogin", false,
+		"Complete browser logins and device authorizations without user interaction.")
+	cmd.Flags().DurationVar(&opts.TokenLifetime, "tokenLifetime", 24*time.Hour, "Lifetime of the issued access tokens.")
+	cmd.Flags().StringVar(&opts.UserID, "userID", "", "User ID of the logged in user.")
+	cmd.Flags().StringVar(&opts.OrgID, "orgID", "", "IMS Organization of the logged in user.")
+
+	return cmd
+}
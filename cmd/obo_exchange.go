@@ -31,8 +31,7 @@ func oboExchangeCmd(imsConfig *ims.Config) *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("error during On-Behalf-Of exchange: %w", err)
 			}
-			fmt.Println(resp.AccessToken)
-			return nil
+			return printToken(cmd, imsConfig, resp.AccessToken)
 		},
 	}
 
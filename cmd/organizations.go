@@ -28,13 +28,11 @@ func organizationsCmd(imsConfig *ims.Config) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.SilenceUsage = true
 
-
 			resp, err := imsConfig.GetOrganizations()
 			if err != nil {
 				return fmt.Errorf("error in get organizations cmd: %w", err)
 			}
-			fmt.Println(prettify.JSON(resp))
-			return nil
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, prettify.RawJSON(resp), prettify.JSON(resp))
 		},
 	}
 
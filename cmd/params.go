@@ -12,6 +12,7 @@ package cmd
 
 import (
 	"fmt"
+	"log"
 	"os"
 
 	"github.com/adobe/imscli/ims"
@@ -32,6 +33,10 @@ func initParams(cmd *cobra.Command, params *ims.Config, configFile string) error
 	// Setup env vars
 	v.SetEnvPrefix("ims")
 	v.AutomaticEnv()
+	// Parameters without a flag are only unmarshalled when bound explicitly.
+	if err := v.BindEnv(secretPassphraseKey); err != nil {
+		return fmt.Errorf("unable to process environment variables: %w", err)
+	}
 
 	// Command flags (local + inherited persistent flags)
 	err := v.BindPFlags(cmd.Flags())
@@ -68,6 +73,16 @@ func initParams(cmd *cobra.Command, params *ims.Config, configFile string) error
 		}
 	}
 
+	err = applyContext(cmd, v)
+	if err != nil {
+		return err
+	}
+
+	err = resolveSecrets(cmd, v)
+	if err != nil {
+		return err
+	}
+
 	err = v.Unmarshal(params)
 	if err != nil {
 		return fmt.Errorf("unable to parse configuration file: %w", err)
@@ -75,3 +90,30 @@ func initParams(cmd *cobra.Command, params *ims.Config, configFile string) error
 
 	return nil
 }
+
+// applyContext merges the settings of the selected named context into the
+// configuration file layer, so they override the top-level values of the file
+// while environment variables and flags still take precedence. The context is
+// selected with the --context flag (or IMS_CONTEXT), falling back to the
+// current-context key of the configuration file.
+func applyContext(cmd *cobra.Command, v *viper.Viper) error {
+	name := v.GetString(contextKey)
+	if name == "" {
+		name = v.GetString(currentContextKey)
+	}
+	if name == "" {
+		return nil
+	}
+
+	settings, ok := v.Get(contextsKey + "." + name).(map[string]any)
+	if !ok {
+		// The context commands must keep working to fix a dangling current-context.
+		if skipsContext(cmd) {
+			return nil
+		}
+		return fmt.Errorf("context %q not found in the configuration file", name)
+	}
+	log.Printf("Using context %s.", name)
+
+	return v.MergeConfigMap(settings)
+}
@@ -0,0 +1,274 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package prettify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/template"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Output formats accepted by Render. The template format takes the template
+// text after an equal sign, e.g. template={{.access_token}}.
+const (
+	FormatText     = "text"
+	FormatJSON     = "json"
+	FormatYAML     = "yaml"
+	FormatEnv      = "env"
+	FormatTemplate = "template"
+)
+
+// envPrefix is prepended to the variable names of the env format, matching the
+// prefix of the environment variables read by imscli.
+const envPrefix = "IMS_"
+
+// ValidateFormat checks the value of the --output flag.
+func ValidateFormat(format string) error {
+	name, tmpl, hasTmpl := strings.Cut(format, "=")
+	switch name {
+	case FormatText, FormatJSON, FormatYAML, FormatEnv:
+		if hasTmpl {
+			return fmt.Errorf("the %s output format takes no argument", name)
+		}
+		return nil
+	case FormatTemplate:
+		if !hasTmpl || tmpl == "" {
+			return fmt.Errorf("the template output format requires a template, e.g. template={{.access_token}}")
+		}
+		if _, err := template.New("output").Parse(tmpl); err != nil {
+			return fmt.Errorf("invalid output template: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q, supported formats are text, json, yaml, env and template=<template>", format)
+	}
+}
+
+// RawJSON wraps a JSON document received from IMS so that Render embeds it
+// as-is instead of encoding it as a string. Invalid JSON is kept as a string.
+func RawJSON(s string) any {
+	if json.Valid([]byte(s)) {
+		return json.RawMessage(s)
+	}
+	return s
+}
+
+// Render writes the result of a command in the requested format. The data is
+// the machine-readable result, rendered through its JSON representation so
+// that all formats share the same field names. The text is the human-readable
+// output of the text format.
+func Render(w io.Writer, format string, data any, text string) error {
+	if err := ValidateFormat(format); err != nil {
+		return err
+	}
+	name, tmpl, _ := strings.Cut(format, "=")
+
+	switch name {
+	case FormatText:
+		_, err := fmt.Fprintln(w, text)
+		return err
+	case FormatJSON:
+		return renderJSON(w, data)
+	}
+
+	generic, err := normalize(data)
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(generic); err != nil {
+			return fmt.Errorf("error rendering YAML output: %w", err)
+		}
+		return enc.Close()
+	case FormatEnv:
+		return renderEnv(w, generic)
+	default:
+		t, err := template.New("output").Option("missingkey=error").Parse(tmpl)
+		if err != nil {
+			return fmt.Errorf("invalid output template: %w", err)
+		}
+		if err := t.Execute(w, generic); err != nil {
+			return fmt.Errorf("error rendering output template: %w", err)
+		}
+		_, err = fmt.Fprintln(w)
+		return err
+	}
+}
+
+func renderJSON(w io.Writer, data any) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(data); err != nil {
+		return fmt.Errorf("error rendering JSON output: %w", err)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// normalize converts the data to the generic maps and slices produced by
+// decoding its JSON representation. Numbers are kept as integers when
+// possible, so YAML and env outputs do not print them in scientific notation.
+func normalize(data any) (any, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding output: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("error encoding output: %w", err)
+	}
+	return convertNumbers(generic), nil
+}
+
+func convertNumbers(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = convertNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = convertNumbers(item)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	}
+	return v
+}
+
+// renderEnv prints one shell export line per field. Nested objects are
+// flattened joining the keys with underscores, lists of scalars are joined
+// with commas and any other list is exported as JSON. A result that is not an
+// object is exported as IMS_RESULT.
+func renderEnv(w io.Writer, data any) error {
+	vars := map[string]string{}
+	if m, ok := data.(map[string]any); ok {
+		if err := flattenEnv(vars, "", m); err != nil {
+			return err
+		}
+	} else {
+		value, err := envValue(data)
+		if err != nil {
+			return err
+		}
+		vars["RESULT"] = value
+	}
+
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if _, err := fmt.Fprintf(w, "export %s%s=%s\n", envPrefix, name, shellQuote(vars[name])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func flattenEnv(vars map[string]string, prefix string, m map[string]any) error {
+	for k, v := range m {
+		name := prefix + envName(k)
+		if nested, ok := v.(map[string]any); ok {
+			if err := flattenEnv(vars, name+"_", nested); err != nil {
+				return err
+			}
+			continue
+		}
+		value, err := envValue(v)
+		if err != nil {
+			return err
+		}
+		vars[name] = value
+	}
+	return nil
+}
+
+func envValue(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]any, []any:
+				b, err := json.Marshal(v)
+				if err != nil {
+					return "", fmt.Errorf("error encoding output: %w", err)
+				}
+				return string(b), nil
+			}
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, ","), nil
+	case map[string]any:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("error encoding output: %w", err)
+		}
+		return string(b), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// envName converts a JSON field name to an environment variable name:
+// uppercase, with any character other than letters and digits replaced by an
+// underscore, and camelCase words split.
+func envName(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			if i > 0 && key[i-1] >= 'a' && key[i-1] <= 'z' {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// shellQuote quotes a value for POSIX shells, so the output can be used with
+// eval "$(imscli ... --output env)".
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
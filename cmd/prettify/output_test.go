@@ -0,0 +1,165 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package prettify
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type sampleResult struct {
+	AccessToken string   `json:"access_token"`
+	ExpiresIn   int64    `json:"expires_in"`
+	Scopes      []string `json:"scopes"`
+	Profile     any      `json:"profile"`
+}
+
+var sample = sampleResult{
+	AccessToken: "it's-a-token",
+	ExpiresIn:   86400000,
+	Scopes:      []string{"openid", "AdobeID"},
+	Profile:     RawJSON(`{"userId":"u1","account":{"type":"type1"}}`),
+}
+
+func TestValidateFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr string
+	}{
+		{format: "text"},
+		{format: "json"},
+		{format: "yaml"},
+		{format: "env"},
+		{format: "template={{.access_token}}"},
+		{format: "xml", wantErr: "unknown output format"},
+		{format: "", wantErr: "unknown output format"},
+		{format: "json=x", wantErr: "takes no argument"},
+		{format: "template", wantErr: "requires a template"},
+		{format: "template=", wantErr: "requires a template"},
+		{format: "template={{.access_token", wantErr: "invalid output template"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			err := ValidateFormat(tt.format)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("error = %v, want to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   any
+		want   string
+	}{
+		{
+			name:   "text prints the text representation",
+			format: "text",
+			data:   sample,
+			want:   "human readable\n",
+		},
+		{
+			name:   "json embeds raw JSON",
+			format: "json",
+			data:   sample,
+			want: `{
+  "access_token": "it's-a-token",
+  "expires_in": 86400000,
+  "scopes": [
+    "openid",
+    "AdobeID"
+  ],
+  "profile": {
+    "userId": "u1",
+    "account": {
+      "type": "type1"
+    }
+  }
+}
+`,
+		},
+		{
+			name:   "yaml keeps integers",
+			format: "yaml",
+			data:   sample,
+			want: `access_token: it's-a-token
+expires_in: 86400000
+profile:
+  account:
+    type: type1
+  userId: u1
+scopes:
+  - openid
+  - AdobeID
+`,
+		},
+		{
+			name:   "env flattens and quotes",
+			format: "env",
+			data:   sample,
+			want: `export IMS_ACCESS_TOKEN='it'\''s-a-token'
+export IMS_EXPIRES_IN='86400000'
+export IMS_PROFILE_ACCOUNT_TYPE='type1'
+export IMS_PROFILE_USER_ID='u1'
+export IMS_SCOPES='openid,AdobeID'
+`,
+		},
+		{
+			name:   "env exports non-objects as IMS_RESULT",
+			format: "env",
+			data:   RawJSON(`[{"orgName":"o1"}]`),
+			want:   "export IMS_RESULT='[{\"orgName\":\"o1\"}]'\n",
+		},
+		{
+			name:   "template uses the JSON field names",
+			format: "template=Bearer {{.access_token}} {{.profile.userId}}",
+			data:   sample,
+			want:   "Bearer it's-a-token u1\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, tt.format, tt.data, "human readable"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Render()\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, "template={{.missing}}", sample, ""); err == nil {
+		t.Error("expected an error for a missing template key")
+	}
+	if err := Render(&buf, "csv", sample, ""); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestRawJSON(t *testing.T) {
+	if _, ok := RawJSON(`{"a":1}`).(string); ok {
+		t.Error("valid JSON must not be wrapped as a string")
+	}
+	if s, ok := RawJSON("not json").(string); !ok || s != "not json" {
+		t.Errorf("invalid JSON must be kept as a string, got %#v", RawJSON("not json"))
+	}
+}
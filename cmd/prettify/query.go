@@ -0,0 +1,385 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package prettify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// queryStep is a step of a query, applied to every node matched so far.
+type queryStep struct {
+	key      string
+	index    int
+	wildcard bool
+	isIndex  bool
+	filter   *queryFilter
+}
+
+// queryFilter keeps the children having the field at path, and when op is
+// set, whose field is equal (==) or not (!=) to value.
+type queryFilter struct {
+	path  []string
+	op    string
+	value any
+}
+
+// Select narrows a typed result down to the given top-level fields, or the
+// JSON document it was parsed from to the result of a query, so that queries
+// reach the fields the typed result leaves out. It returns the data and text
+// to render: without fields nor query, the typed result as data and the whole
+// document as text.
+func Select(typed any, doc string, fields []string, query string) (any, string, error) {
+	if len(fields) == 0 && query == "" {
+		return typed, JSON(doc), nil
+	}
+	if len(fields) > 0 && query != "" {
+		return nil, "", fmt.Errorf("fields and query are mutually exclusive")
+	}
+	source := []byte(doc)
+	if query == "" {
+		var err error
+		if source, err = json.Marshal(typed); err != nil {
+			return nil, "", fmt.Errorf("error encoding the result: %w", err)
+		}
+	}
+	v, err := decode(source)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to parse the document: %w", err)
+	}
+
+	var result any
+	if query != "" {
+		result, err = Query(v, query)
+	} else {
+		result, err = selectFields(v, fields)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	text, err := selectionText(result)
+	return result, text, err
+}
+
+// Query evaluates a JSONPath-like expression on a document decoded from JSON.
+// It supports $ (optional), .name, ['name'], [n] with negative indexes
+// counting from the end, .* and [*], and the filters [?(@.name)],
+// [?(@.name == value)] and [?(@.name != value)]. Expressions with a wildcard
+// or a filter return the list of the matches, the others the single match.
+func Query(doc any, expr string) (any, error) {
+	steps, err := parseQuery(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query %q: %w", expr, err)
+	}
+
+	multi := false
+	nodes := []any{doc}
+	for _, step := range steps {
+		multi = multi || step.wildcard || step.filter != nil
+		var next []any
+		for _, node := range nodes {
+			next = append(next, step.apply(node)...)
+		}
+		nodes = next
+	}
+	if multi {
+		if nodes == nil {
+			nodes = []any{}
+		}
+		return nodes, nil
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no match for query %q", expr)
+	}
+	return nodes[0], nil
+}
+
+func (s queryStep) apply(node any) []any {
+	switch {
+	case s.filter != nil:
+		var matches []any
+		for _, child := range children(node) {
+			if s.filter.matches(child) {
+				matches = append(matches, child)
+			}
+		}
+		return matches
+	case s.wildcard:
+		return children(node)
+	case s.isIndex:
+		list, ok := node.([]any)
+		if !ok {
+			return nil
+		}
+		n := s.index
+		if n < 0 {
+			n += len(list)
+		}
+		if n < 0 || n >= len(list) {
+			return nil
+		}
+		return []any{list[n]}
+	default:
+		if v, ok := lookupField(node, []string{s.key}); ok {
+			return []any{v}
+		}
+		return nil
+	}
+}
+
+// children returns the items of a list or the values of an object, in the
+// order of their keys.
+func children(node any) []any {
+	switch node := node.(type) {
+	case []any:
+		return node
+	case map[string]any:
+		keys := make([]string, 0, len(node))
+		for k := range node {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		values := make([]any, 0, len(keys))
+		for _, k := range keys {
+			values = append(values, node[k])
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func (f *queryFilter) matches(node any) bool {
+	v, ok := lookupField(node, f.path)
+	switch {
+	case !ok:
+		return false
+	case f.op == "":
+		return true
+	case f.op == "==":
+		return equal(v, f.value)
+	default:
+		return !equal(v, f.value)
+	}
+}
+
+// equal compares two decoded JSON values, the numbers by value rather than by
+// text, so that 1 equals 1.0 and 1e0.
+func equal(a, b any) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, okA := new(big.Rat).SetString(a.String())
+		y, okB := new(big.Rat).SetString(b.String())
+		return okA && okB && x.Cmp(y) == 0
+	case []any:
+		b, ok := b.([]any)
+		return ok && slices.EqualFunc(a, b, equal)
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		return ok && maps.EqualFunc(a, b, equal)
+	default:
+		return a == b
+	}
+}
+
+func lookupField(node any, path []string) (any, bool) {
+	for _, key := range path {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if node, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+func parseQuery(expr string) ([]queryStep, error) {
+	rest := strings.TrimSpace(expr)
+	rest = strings.TrimPrefix(rest, "$")
+	if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest
+	}
+
+	var steps []queryStep
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+			switch name {
+			case "":
+				return nil, fmt.Errorf("missing field name")
+			case "*":
+				steps = append(steps, queryStep{wildcard: true})
+			default:
+				steps = append(steps, queryStep{key: name})
+			}
+		case '[':
+			end := closingBracket(rest)
+			if end < 0 {
+				return nil, fmt.Errorf("missing ]")
+			}
+			step, err := parseBracket(strings.TrimSpace(rest[1:end]))
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected %q", rest[0])
+		}
+	}
+	return steps, nil
+}
+
+// closingBracket returns the index of the ] closing the bracket at the start
+// of s, ignoring the brackets in quoted strings.
+func closingBracket(s string) int {
+	return indexUnquoted(s, 1, func(i int) bool { return s[i] == ']' })
+}
+
+// indexUnquoted returns the first index of s from start, outside single and
+// double quotes, where match is true, or -1.
+func indexUnquoted(s string, start int, match func(i int) bool) int {
+	var quote byte
+	for i := start; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case match(i):
+			return i
+		}
+	}
+	return -1
+}
+
+func parseBracket(inner string) (queryStep, error) {
+	switch {
+	case inner == "*":
+		return queryStep{wildcard: true}, nil
+	case strings.HasPrefix(inner, "?"):
+		filter, err := parseFilter(strings.TrimPrefix(inner, "?"))
+		if err != nil {
+			return queryStep{}, err
+		}
+		return queryStep{filter: filter}, nil
+	case isQuoted(inner):
+		return queryStep{key: inner[1 : len(inner)-1]}, nil
+	}
+	n, err := strconv.Atoi(inner)
+	if err != nil {
+		return queryStep{}, fmt.Errorf("invalid index %q", inner)
+	}
+	return queryStep{index: n, isIndex: true}, nil
+}
+
+func parseFilter(s string) (*queryFilter, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	left, right, op := s, "", ""
+	i := indexUnquoted(s, 0, func(i int) bool {
+		return strings.HasPrefix(s[i:], "==") || strings.HasPrefix(s[i:], "!=")
+	})
+	if i >= 0 {
+		left, right, op = strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+2:]), s[i:i+2]
+	}
+	path, ok := strings.CutPrefix(left, "@.")
+	if !ok || path == "" {
+		return nil, fmt.Errorf("invalid filter %q, expected @.field, @.field == value or @.field != value", s)
+	}
+
+	f := &queryFilter{path: strings.Split(path, "."), op: op}
+	if op != "" {
+		if isQuoted(right) {
+			f.value = right[1 : len(right)-1]
+		} else {
+			v, err := decode([]byte(right))
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter value %q, quote the strings", right)
+			}
+			f.value = v
+		}
+	}
+	return f, nil
+}
+
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]
+}
+
+// decode parses a JSON value, keeping the numbers as written.
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return v, nil
+}
+
+func selectFields(doc any, fields []string) (any, error) {
+	m, ok := doc.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("the document is not an object, use a query instead of fields")
+	}
+	selected := make(map[string]any, len(fields))
+	for _, field := range fields {
+		v, ok := m[field]
+		if !ok {
+			return nil, fmt.Errorf("field %q not found in the document", field)
+		}
+		selected[field] = v
+	}
+	return selected, nil
+}
+
+// selectionText prints strings and numbers as-is, for scripts, and anything
+// else as indented JSON.
+func selectionText(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error encoding output: %w", err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package prettify
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const sampleProfile = `{
+  "userId": "u1@AdobeID",
+  "email": "user@example.com",
+  "created": 1700000000000,
+  "roles": [
+    {"named_role": "org_admin", "target_type": "TRG_ORG", "organization": "O1@AdobeOrg"},
+    {"named_role": "user", "target_type": "TRG_ORG", "organization": "O2@AdobeOrg"},
+    {"named_role": "developer", "target_type": "TRG_PRODUCT"}
+  ],
+  "projectedProductContext": [
+    {"prodCtx": {"serviceCode": "dma_tartan", "fulfillable_data": "abc"}},
+    {"prodCtx": {"serviceCode": "creative_cloud"}}
+  ],
+  "odd key": {"a": 1, "b": 2}
+}`
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    string
+		wantErr string
+	}{
+		{query: "$", want: `"userId":"u1@AdobeID"`},
+		{query: "$.email", want: `"user@example.com"`},
+		{query: "email", want: `"user@example.com"`},
+		{query: "created", want: `1700000000000`},
+		{query: "$.roles[0].named_role", want: `"org_admin"`},
+		{query: "$.roles[-1].named_role", want: `"developer"`},
+		{query: "$['odd key'].b", want: `2`},
+		{query: `$["odd key"].*`, want: `[1,2]`},
+		{query: "$.roles[*].named_role", want: `["org_admin","user","developer"]`},
+		{query: "$.roles[?(@.organization)].named_role", want: `["org_admin","user"]`},
+		{query: "$.roles[?(@.target_type == 'TRG_ORG')].organization", want: `["O1@AdobeOrg","O2@AdobeOrg"]`},
+		{query: "$.roles[?(@.named_role != 'user')].named_role", want: `["org_admin","developer"]`},
+		{query: "$.projectedProductContext[?(@.prodCtx.fulfillable_data)].prodCtx.serviceCode", want: `["dma_tartan"]`},
+		{query: "$.roles[?(@.named_role == 'nobody')]", want: `[]`},
+		{query: "$.roles[?(@.named_role != 'x==y')].named_role", want: `["org_admin","user","developer"]`},
+		{query: `$.roles[?(@.named_role == "a!=b")]`, want: `[]`},
+		{query: "$[?(@.a == 1.0)].b", want: `[2]`},
+		{query: "$[?(@.a != 1e0)].b", want: `[]`},
+		{query: "$.missing", wantErr: "no match"},
+		{query: "$.roles[3]", wantErr: "no match"},
+		{query: "$.roles[", wantErr: "missing ]"},
+		{query: "$.roles[x]", wantErr: "invalid index"},
+		{query: "$..email", wantErr: "missing field name"},
+		{query: "$.roles[?(named_role)]", wantErr: "invalid filter"},
+		{query: "$.roles[?(@.named_role == user)]", wantErr: "quote the strings"},
+	}
+	doc, err := decode([]byte(sampleProfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := Query(doc, tt.query)
+			switch {
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want to contain %q", err, tt.wantErr)
+				}
+				return
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+			b, err := json.Marshal(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(b), tt.want) {
+				t.Errorf("got %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	typed := struct {
+		UserID string `json:"userId"`
+		Email  string `json:"email"`
+	}{UserID: "u1@AdobeID", Email: "user@example.com"}
+	if data, _, err := Select(typed, sampleProfile, nil, ""); err != nil || data != any(typed) {
+		t.Errorf("data = %v, %v, want the typed result", data, err)
+	}
+
+	tests := []struct {
+		name     string
+		fields   []string
+		query    string
+		wantText string
+		wantErr  string
+	}{
+		{name: "whole document", wantText: `"created": 1700000000000`},
+		{name: "fields", fields: []string{"userId", "email"},
+			wantText: "{\n  \"email\": \"user@example.com\",\n  \"userId\": \"u1@AdobeID\"\n}"},
+		{name: "string", query: "email", wantText: "user@example.com"},
+		{name: "number", query: "created", wantText: "1700000000000"},
+		{name: "list", query: "roles[*].named_role", wantText: "[\n  \"org_admin\","},
+		{name: "untyped field", fields: []string{"email", "created"}, wantErr: `field "created" not found`},
+		{name: "both", fields: []string{"email"}, query: "email", wantErr: "mutually exclusive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, text, err := Select(typed, sampleProfile, tt.fields, tt.query)
+			switch {
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want to contain %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case !strings.Contains(text, tt.wantText):
+				t.Errorf("text = %q, want to contain %q", text, tt.wantText)
+			}
+		})
+	}
+
+	if _, _, err := Select([]int{1, 2}, `[1, 2]`, []string{"a"}, ""); err == nil || !strings.Contains(err.Error(), "not an object") {
+		t.Errorf("fields of a list: error = %v, want not an object", err)
+	}
+}
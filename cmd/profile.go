@@ -19,6 +19,8 @@ import (
 )
 
 func profileCmd(imsConfig *ims.Config) *cobra.Command {
+	var fields []string
+	var query string
 
 	cmd := &cobra.Command{
 		Use:   "profile",
@@ -27,19 +29,27 @@ func profileCmd(imsConfig *ims.Config) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.SilenceUsage = true
 
-
-			resp, err := imsConfig.GetProfile()
+			profile, err := imsConfig.GetProfile()
 			if err != nil {
 				return fmt.Errorf("error in get profile cmd: %w", err)
 			}
-			fmt.Println(prettify.JSON(resp))
-			return nil
+			data, text, err := prettify.Select(profile, profile.Body, fields, query)
+			if err != nil {
+				return fmt.Errorf("error in get profile cmd: %w", err)
+			}
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, text)
 		},
 	}
 
 	cmd.Flags().StringVarP(&imsConfig.AccessToken, "accessToken", "t", "", "Access token.")
 	cmd.Flags().StringVarP(&imsConfig.ProfileAPIVersion, "profileApiVersion", "a", "v1", "Profile API version.")
 	cmd.Flags().BoolVarP(&imsConfig.DecodeFulfillableData, "decodeFulfillableData", "d", false, "Decode the fulfillable_data in the product context.")
+	cmd.Flags().StringToStringVar(&imsConfig.FulfillableDataDecoders, "fulfillableDataDecoders", nil,
+		"Encoding (gzip, base64 or auto) of the fulfillable_data of more service codes, e.g. my_service=base64.")
+
+	cmd.Flags().StringSliceVar(&fields, "fields", nil, "Top-level fields of the typed profile to print, e.g. email,userId,roles.")
+	cmd.Flags().StringVar(&query, "query", "", "JSONPath-like query selecting a part of the profile, e.g. $.roles[*].named_role.")
+	cmd.MarkFlagsMutuallyExclusive("fields", "query")
 
 	return cmd
 }
@@ -0,0 +1,40 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// errNoTerminal is returned by promptSecret when stdin is not a terminal.
+var errNoTerminal = errors.New("stdin is not a terminal")
+
+// promptSecret reads a secret typed by the user without echoing it. The prompt
+// goes to stderr so it does not mix with the output of the command.
+func promptSecret(cmd *cobra.Command, prompt string) (string, error) {
+	f, ok := cmd.InOrStdin().(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return "", errNoTerminal
+	}
+	fmt.Fprint(cmd.ErrOrStderr(), prompt)
+	secret, err := term.ReadPassword(int(f.Fd()))
+	fmt.Fprintln(cmd.ErrOrStderr())
+	if err != nil {
+		return "", fmt.Errorf("error reading the terminal: %w", err)
+	}
+	defer clear(secret)
+	return string(secret), nil
+}
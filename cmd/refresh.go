@@ -14,6 +14,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/adobe/imscli/cmd/prettify"
 	"github.com/adobe/imscli/ims"
 	"github.com/spf13/cobra"
 )
@@ -31,20 +32,20 @@ func refreshCmd(imsConfig *ims.Config) *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("error during the token refresh: %w", err)
 			}
+			data := struct {
+				AccessToken  string `json:"access_token"`
+				RefreshToken string `json:"refresh_token"`
+			}{resp.AccessToken, resp.RefreshToken}
+
+			text := resp.AccessToken
 			if imsConfig.FullOutput {
-				data := struct {
-					AccessToken  string `json:"access_token"`
-					RefreshToken string `json:"refresh_token"`
-				}{resp.AccessToken, resp.RefreshToken}
 				jsonData, err := json.MarshalIndent(data, "", "  ")
 				if err != nil {
 					return fmt.Errorf("error marshalling full JSON response: %w", err)
 				}
-				fmt.Printf("%s\n", jsonData)
-				return nil
+				text = string(jsonData)
 			}
-			fmt.Println(resp.AccessToken)
-			return nil
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, text)
 		},
 	}
 
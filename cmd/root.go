@@ -16,8 +16,11 @@ package cmd
 import (
 	"io"
 	"log"
+	"time"
 
+	"github.com/adobe/imscli/cmd/prettify"
 	"github.com/adobe/imscli/ims"
+	"github.com/adobe/imscli/secretstore"
 	"github.com/spf13/cobra"
 )
 
@@ -38,18 +41,50 @@ func RootCmd(version string) *cobra.Command {
 			}
 			// This call of the initParams will load all env vars, config file and flags.
 			imsConfig.Verbose = verbose
-			return initParams(cmd, imsConfig, configFile)
+			if err := initParams(cmd, imsConfig, configFile); err != nil {
+				return err
+			}
+			return prettify.ValidateFormat(imsConfig.Output)
 		},
 	}
 	cmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output.")
 	cmd.PersistentFlags().StringVarP(&imsConfig.URL, "url", "U", "https://ims-na1.adobelogin.com",
 		"IMS Endpoint URL.")
 	cmd.PersistentFlags().StringVarP(&imsConfig.ProxyURL, "proxyUrl", "P", "",
-		"Connect to IMS through the specified proxy. Specified as http(s)://host:port.")
+		"Connect to IMS through the specified proxy instead of the proxy of HTTPS_PROXY. Specified as "+
+			"http(s)://[user:password@]host:port or socks5://[user:password@]host:port.")
+	cmd.PersistentFlags().StringVar(&imsConfig.ProxyUser, "proxyUser", "",
+		"User of the proxy, when not in the proxy URL.")
+	cmd.PersistentFlags().StringVar(&imsConfig.ProxyPassword, "proxyPassword", "",
+		"Password of the proxy, when not in the proxy URL.")
+	cmd.PersistentFlags().StringSliceVar(&imsConfig.NoProxy, "noProxy", nil,
+		"Hosts, domains and CIDR ranges to connect to without proxy, like NO_PROXY.")
 	cmd.PersistentFlags().BoolVarP(&imsConfig.ProxyIgnoreTLS, "proxyIgnoreTLS", "T", false,
-		"Ignore TLS certificate verification (only valid when connecting through a proxy).")
+		"Ignore TLS certificate verification of the connections through an HTTP(S) proxy, e.g. a TLS inspecting proxy.")
+	cmd.PersistentFlags().StringVar(&imsConfig.CAFile, "caFile", "",
+		"PEM file of additional CAs to trust, e.g. the CA of a TLS inspecting proxy.")
+	cmd.PersistentFlags().StringVar(&imsConfig.ClientCert, "clientCert", "",
+		"PEM client certificate for the IMS gateways requiring mutual TLS.")
+	cmd.PersistentFlags().StringVar(&imsConfig.ClientKey, "clientKey", "",
+		"PEM private key of the client certificate, when not in the certificate file.")
 	cmd.PersistentFlags().StringVarP(&configFile, "configFile", "f", "", "Configuration file.")
+	cmd.PersistentFlags().String("context", "",
+		"Named context of the configuration file to use instead of the current context.")
 	cmd.PersistentFlags().IntVar(&imsConfig.Timeout, "timeout", 30, "HTTP client timeout in seconds.")
+	cmd.PersistentFlags().IntVar(&imsConfig.Retries, "retries", 3,
+		"Retries of the safe IMS calls failing with a transient error (429, 502, 503, 504, connection reset).")
+	cmd.PersistentFlags().DurationVar(&imsConfig.RetryMaxWait, "retryMaxWait", 30*time.Second,
+		"Maximum delay between two retries, also honoring Retry-After up to this delay.")
+	cmd.PersistentFlags().BoolVar(&imsConfig.Trace, "trace", false,
+		"Dump the HTTP requests and responses to stderr, with the secrets redacted.")
+	cmd.PersistentFlags().StringVar(&imsConfig.TraceHAR, "traceHAR", "",
+		"Record the HTTP requests and responses, with the secrets redacted, to a HAR file.")
+	cmd.PersistentFlags().BoolVar(&imsConfig.NoCache, "noCache", false,
+		"Do not reuse nor store tokens in the local token cache.")
+	cmd.PersistentFlags().StringVar(&imsConfig.SecretStore, "secretStore", secretstore.BackendAuto,
+		"Secret store of the keyring: references: auto, file or secret-service.")
+	cmd.PersistentFlags().StringVarP(&imsConfig.Output, "output", "O", prettify.FormatText,
+		"Output format: text, json, yaml, env or template=<Go template>.")
 
 	cmd.AddCommand(
 		oboExchangeCmd(imsConfig),
@@ -60,9 +95,18 @@ func RootCmd(version string) *cobra.Command {
 		exchangeCmd(imsConfig),
 		invalidateCmd(imsConfig),
 		decodeCmd(imsConfig),
+		jwtCmd(imsConfig),
 		refreshCmd(imsConfig),
 		adminCmd(imsConfig),
-		dcrCmd(imsConfig),
+		dcrCmd(&configFile, imsConfig),
+		execProcessCmd(imsConfig),
+		agentCmd(imsConfig),
+		tokenCmd(imsConfig),
+		cacheCmd(imsConfig),
+		secretCmd(imsConfig),
+		keysCmd(imsConfig),
+		contextCmd(&configFile, imsConfig),
+		mockCmd(),
 		completionCmd(),
 	)
 	return cmd
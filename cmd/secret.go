@@ -0,0 +1,190 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/adobe/imscli/cmd/prettify"
+	"github.com/adobe/imscli/ims"
+	"github.com/adobe/imscli/secretstore"
+	"github.com/spf13/cobra"
+)
+
+func secretCmd(imsConfig *ims.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secret",
+		Short: "Manage the secrets of the secret store.",
+		Long: `The secret command manages the secrets kept in the secret store, so client secrets and refresh tokens do not
+need to be written in the configuration file. Any secret parameter can then reference a stored secret as
+keyring:<name>, e.g. clientSecret: keyring:my-client.
+
+The store is selected with the global --secretStore flag: the freedesktop Secret Service (GNOME Keyring, KWallet)
+through secret-tool, or a file encrypted with a passphrase read from IMS_SECRETPASSPHRASE or prompted on the terminal.
+By default the Secret Service is used when available.
+
+This command has no effect by itself, the operation needs to be specified as a subcommand.
+`,
+	}
+	cmd.AddCommand(
+		secretSetCmd(imsConfig),
+		secretGetCmd(imsConfig),
+		secretDeleteCmd(imsConfig),
+		secretListCmd(imsConfig),
+	)
+	return cmd
+}
+
+// openSecretStore opens the configured secret store. The passphrase of the
+// encrypted file is prompted when it is not configured, twice when the file
+// is created.
+func openSecretStore(cmd *cobra.Command, backend, passphrase string) (secretstore.SecretStore, error) {
+	return secretstore.Open(backend, func(create bool) (string, error) {
+		if passphrase != "" {
+			return passphrase, nil
+		}
+		prompt := "Secret store passphrase: "
+		if create {
+			prompt = "New secret store passphrase: "
+		}
+		p, err := promptSecret(cmd, prompt)
+		if errors.Is(err, errNoTerminal) {
+			return "", fmt.Errorf("missing secret store passphrase, set IMS_SECRETPASSPHRASE or run in a terminal")
+		}
+		if err != nil || !create {
+			return p, err
+		}
+		confirm, err := promptSecret(cmd, "Confirm the passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if p != confirm {
+			return "", fmt.Errorf("the passphrases do not match")
+		}
+		return p, nil
+	})
+}
+
+func secretSetCmd(imsConfig *ims.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <name>",
+		Short: "Store a secret.",
+		Long: "Store a secret under the given name, replacing any previous value. The secret is read from stdin, or " +
+			"prompted without echo on a terminal.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			name := args[0]
+			if err := secretstore.ValidName(name); err != nil {
+				return err
+			}
+			secret, err := promptSecret(cmd, "Secret: ")
+			if errors.Is(err, errNoTerminal) {
+				var data []byte
+				data, err = io.ReadAll(cmd.InOrStdin())
+				secret = strings.TrimRight(string(data), "\r\n")
+				clear(data)
+			}
+			if err != nil {
+				return fmt.Errorf("error reading the secret: %w", err)
+			}
+			if secret == "" {
+				return fmt.Errorf("empty secret")
+			}
+
+			store, err := openSecretStore(cmd, imsConfig.SecretStore, imsConfig.SecretPassphrase)
+			if err != nil {
+				return err
+			}
+			if err := store.Set(name, secret); err != nil {
+				return fmt.Errorf("error storing the secret: %w", err)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Secret %s stored, reference it as %s%s\n", name, keyringSecretPrefix, name)
+			return nil
+		},
+	}
+}
+
+func secretGetCmd(imsConfig *ims.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <name>",
+		Short: "Print a secret.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			store, err := openSecretStore(cmd, imsConfig.SecretStore, imsConfig.SecretPassphrase)
+			if err != nil {
+				return err
+			}
+			secret, err := store.Get(args[0])
+			if err != nil {
+				return fmt.Errorf("error reading the secret: %w", err)
+			}
+			data := struct {
+				Name   string `json:"name"`
+				Secret string `json:"secret"`
+			}{args[0], secret}
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, secret)
+		},
+	}
+}
+
+func secretDeleteCmd(imsConfig *ims.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:     "delete <name>",
+		Aliases: []string{"rm"},
+		Short:   "Delete a secret.",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			store, err := openSecretStore(cmd, imsConfig.SecretStore, imsConfig.SecretPassphrase)
+			if err != nil {
+				return err
+			}
+			if err := store.Delete(args[0]); err != nil {
+				return fmt.Errorf("error deleting the secret: %w", err)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Secret %s deleted\n", args[0])
+			return nil
+		},
+	}
+}
+
+func secretListCmd(imsConfig *ims.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the names of the stored secrets.",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			store, err := openSecretStore(cmd, imsConfig.SecretStore, imsConfig.SecretPassphrase)
+			if err != nil {
+				return err
+			}
+			names, err := store.List()
+			if err != nil {
+				return fmt.Errorf("error listing the secrets: %w", err)
+			}
+			if names == nil {
+				names = []string{}
+			}
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, names, strings.Join(names, "\n"))
+		},
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/adobe/imscli/secretstore"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Secret parameters accept references instead of literal values, so secrets do
+// not show up in the process list, the shell history nor the configuration
+// file:
+//   - @file:<path> reads the secret from a file only accessible by its owner.
+//   - "-" reads the secret from stdin.
+//   - env:<NAME> reads the secret from another environment variable.
+//   - cmd:<command> runs a command, e.g. a password manager CLI, and reads the
+//     secret from its output.
+//   - keyring:<name> reads the secret from the imscli secret store.
+const (
+	fileSecretPrefix    = "@file:"
+	envSecretPrefix     = "env:"
+	cmdSecretPrefix     = "cmd:"
+	keyringSecretPrefix = "keyring:"
+	stdinSecret         = "-"
+)
+
+// Parameters of the secret store, global to all the commands. The passphrase
+// of the encrypted file has no flag, it is read from IMS_SECRETPASSPHRASE,
+// the configuration file or a prompt, and accepts references too.
+const (
+	secretStoreKey      = "secretStore"
+	secretPassphraseKey = "secretPassphrase"
+)
+
+// keepSecretRefsAnnotation marks the commands that write their parameters to
+// the configuration file. Their secret references are stored as given, not
+// resolved to the secrets they point to.
+const keepSecretRefsAnnotation = "imscli/keep-secret-refs"
+
+// secretKeys are the parameters holding secrets.
+var secretKeys = []string{
+	"clientSecret", "accessToken", "refreshToken", "serviceToken", "deviceToken", "authorizationCode", "token",
+	"privateKeyPassphrase", "proxyPassword", "registrationAccessToken",
+}
+
+// resolveSecrets replaces the secret references of the parameters used by the
+// command with the secrets they point to. Parameters the command does not
+// take are left alone, so e.g. a password manager is not run for nothing.
+// The commands annotated with keepSecretRefsAnnotation only get the passphrase
+// of the secret store resolved.
+func resolveSecrets(cmd *cobra.Command, v *viper.Viper) error {
+	_, keepRefs := cmd.Annotations[keepSecretRefsAnnotation]
+	var keys, stdinKeys []string
+	// The passphrase of the secret store comes first, the keyring references
+	// may need it.
+	if v.GetString(secretPassphraseKey) != "" {
+		keys = append(keys, secretPassphraseKey)
+		if v.GetString(secretPassphraseKey) == stdinSecret {
+			stdinKeys = append(stdinKeys, secretPassphraseKey)
+		}
+	}
+	for _, key := range secretKeys {
+		if cmd.Flags().Lookup(key) == nil {
+			continue
+		}
+		if keepRefs {
+			if v.GetString(key) == stdinSecret {
+				return fmt.Errorf("the %s parameter cannot be read from stdin when it is saved, use another reference", key)
+			}
+			continue
+		}
+		keys = append(keys, key)
+		if v.GetString(key) == stdinSecret {
+			stdinKeys = append(stdinKeys, key)
+		}
+	}
+	if len(stdinKeys) > 1 {
+		return fmt.Errorf("only one parameter can be read from stdin, got %s", strings.Join(stdinKeys, ", "))
+	}
+
+	r := &secretResolver{cmd: cmd, v: v}
+	for _, key := range keys {
+		if key == secretPassphraseKey && strings.HasPrefix(v.GetString(key), keyringSecretPrefix) {
+			return fmt.Errorf("the secret store passphrase cannot be stored in the secret store")
+		}
+		secret, ok, err := r.resolve(v.GetString(key))
+		if err != nil {
+			return fmt.Errorf("unable to resolve the %s parameter: %w", key, err)
+		}
+		if ok {
+			v.Set(key, secret)
+		}
+	}
+	return nil
+}
+
+// secretResolver resolves the references of a command, opening the secret
+// store only when a keyring reference is found.
+type secretResolver struct {
+	cmd   *cobra.Command
+	v     *viper.Viper
+	store secretstore.SecretStore
+}
+
+// resolve returns the secret a reference points to, and false if the value is
+// a literal secret.
+func (r *secretResolver) resolve(ref string) (string, bool, error) {
+	cmd := r.cmd
+	var data []byte
+	var err error
+	switch {
+	case strings.HasPrefix(ref, keyringSecretPrefix):
+		store, err := r.keyring()
+		if err != nil {
+			return "", false, err
+		}
+		secret, err := store.Get(strings.TrimPrefix(ref, keyringSecretPrefix))
+		if err != nil {
+			return "", false, err
+		}
+		return secret, true, nil
+	case ref == stdinSecret:
+		data, err = io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return "", false, fmt.Errorf("error reading stdin: %w", err)
+		}
+	case strings.HasPrefix(ref, fileSecretPrefix):
+		data, err = readSecretFile(strings.TrimPrefix(ref, fileSecretPrefix))
+		if err != nil {
+			return "", false, err
+		}
+	case strings.HasPrefix(ref, envSecretPrefix):
+		name := strings.TrimPrefix(ref, envSecretPrefix)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", false, fmt.Errorf("environment variable %s is not set", name)
+		}
+		return value, true, nil
+	case strings.HasPrefix(ref, cmdSecretPrefix):
+		data, err = runSecretCommand(cmd, strings.TrimPrefix(ref, cmdSecretPrefix))
+		if err != nil {
+			return "", false, err
+		}
+	default:
+		return ref, false, nil
+	}
+	// The buffer is zeroed once copied, like the private key of the JWT flow.
+	defer clear(data)
+
+	secret := strings.TrimRight(string(data), "\r\n")
+	if secret == "" {
+		return "", false, fmt.Errorf("empty secret")
+	}
+	return secret, true, nil
+}
+
+func (r *secretResolver) keyring() (secretstore.SecretStore, error) {
+	if r.store != nil {
+		return r.store, nil
+	}
+	store, err := openSecretStore(r.cmd, r.v.GetString(secretStoreKey), r.v.GetString(secretPassphraseKey))
+	if err != nil {
+		return nil, err
+	}
+	r.store = store
+	return store, nil
+}
+
+// readSecretFile reads a secret file, refusing files other users can access.
+func readSecretFile(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading secret file: %w", err)
+	}
+	// Windows has no permission bits, the ACLs of the user profile apply.
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return nil, fmt.Errorf("secret file %s is accessible by other users (mode %04o), restrict it with chmod 600",
+			path, info.Mode().Perm())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading secret file: %w", err)
+	}
+	return data, nil
+}
+
+// runSecretCommand runs a command through the shell and returns its output.
+// Its stderr and stdin are the ones of imscli, so it can prompt the user.
+func runSecretCommand(cmd *cobra.Command, command string) ([]byte, error) {
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.Command("cmd", "/C", command)
+	} else {
+		c = exec.Command("sh", "-c", command)
+	}
+	var out bytes.Buffer
+	c.Stdout = &out
+	c.Stderr = cmd.ErrOrStderr()
+	c.Stdin = cmd.InOrStdin()
+	if err := c.Run(); err != nil {
+		clear(out.Bytes())
+		return nil, fmt.Errorf("error running secret command: %w", err)
+	}
+	return out.Bytes(), nil
+}
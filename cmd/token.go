@@ -0,0 +1,87 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/adobe/imscli/cmd/prettify"
+	"github.com/adobe/imscli/ims"
+	"github.com/spf13/cobra"
+)
+
+func tokenCmd(imsConfig *ims.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Get access tokens for scripts.",
+		Long: `The token command gets an access token, negotiated with any of the authorize flows or served by a running
+imscli agent.
+
+This command has no effect by itself, the operation needs to be specified as a subcommand.
+`,
+	}
+	cmd.AddCommand(tokenGetCmd(imsConfig))
+	return cmd
+}
+
+func tokenGetCmd(imsConfig *ims.Config) *cobra.Command {
+	var flow, socket string
+	var fromAgent bool
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Print an access token.",
+		Long: "Print an access token negotiated with the flow given by --flow, or with --agent the token held by the " +
+			"imscli agent listening at --socket, IMS_AGENT_SOCK or the default socket.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			data := struct {
+				AccessToken string `json:"access_token"`
+				ExpiresAt   string `json:"expires_at,omitempty"`
+			}{}
+			if fromAgent {
+				if socket == "" {
+					var err error
+					if socket, err = ims.AgentSocketPath(); err != nil {
+						return err
+					}
+				}
+				resp, err := ims.RequestAgent(socket, ims.AgentGet)
+				if err != nil {
+					return fmt.Errorf("error getting the token from the agent: %w", err)
+				}
+				data.AccessToken, data.ExpiresAt = resp.AccessToken, resp.ExpiresAt
+			} else {
+				if err := checkFlow(flow); err != nil {
+					return err
+				}
+				info, err := imsConfig.Authorize(flow)
+				if err != nil {
+					return fmt.Errorf("error in %s authorization: %w", flow, err)
+				}
+				data.AccessToken = info.AccessToken
+				if !info.ExpiresAt.IsZero() {
+					data.ExpiresAt = info.ExpiresAt.UTC().Format(time.RFC3339)
+				}
+			}
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, data.AccessToken)
+		},
+	}
+
+	cmd.Flags().BoolVar(&fromAgent, "agent", false, "Get the token from the imscli agent.")
+	cmd.Flags().StringVar(&socket, "socket", "", "Path of the agent socket.")
+	addFlowFlags(cmd, imsConfig, &flow)
+
+	return cmd
+}
@@ -14,11 +14,22 @@ package validate
 import (
 	"fmt"
 
+	"github.com/adobe/imscli/cmd/batch"
 	"github.com/adobe/imscli/cmd/prettify"
 	"github.com/adobe/imscli/ims"
 	"github.com/spf13/cobra"
 )
 
+// operation is the batch mode of the validate subcommands.
+var operation = batch.Operation{
+	Verb:       "validate",
+	Participle: "validated",
+	Noun:       "validation",
+	Done:       "valid",
+	Failure:    "is invalid or cannot be validated",
+	Run:        ims.Config.ValidateTokens,
+}
+
 type tokenDef struct {
 	use      string
 	alias    string
@@ -28,14 +39,20 @@ type tokenDef struct {
 }
 
 func tokenCmd(imsConfig *ims.Config, def tokenDef) *cobra.Command {
+	var batchMode *batch.Mode
+
 	cmd := &cobra.Command{
 		Use:     def.use,
 		Aliases: []string{def.alias},
 		Short:   fmt.Sprintf("Validate %s.", def.label),
-		Long:    fmt.Sprintf("Validate %s.", def.label),
+		Long:    fmt.Sprintf("Validate %s.\n\n%s", def.label, operation.Help()),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.SilenceUsage = true
 
+			if batchMode.Enabled() {
+				return batchMode.Run(cmd, *imsConfig, def.use, def.flagName)
+			}
+
 			resp, err := imsConfig.ValidateToken()
 			if err != nil {
 				return fmt.Errorf("error validating the %s: %w", def.label, err)
@@ -43,13 +60,17 @@ func tokenCmd(imsConfig *ims.Config, def tokenDef) *cobra.Command {
 			if !resp.Valid {
 				return fmt.Errorf("invalid token: %v", resp.Info)
 			}
-			fmt.Println(prettify.JSON(resp.Info))
-			return nil
+			data := struct {
+				Valid    bool `json:"valid"`
+				Response any  `json:"response"`
+			}{resp.Valid, prettify.RawJSON(resp.Info)}
+			return prettify.Render(cmd.OutOrStdout(), imsConfig.Output, data, prettify.JSON(resp.Info))
 		},
 	}
 
 	cmd.Flags().StringVarP(def.field, def.flagName, "t", "", def.label+".")
 	cmd.Flags().StringVarP(&imsConfig.ClientID, "clientID", "c", "", "IMS Client ID.")
+	batchMode = batch.AddFlags(cmd, operation)
 
 	return cmd
 }
@@ -45,16 +45,16 @@ func (i Config) validateGetAdminProfileConfig() error {
 }
 
 // GetAdminProfile requests the user profile using a service token.
-func (i Config) GetAdminProfile() (string, error) {
+func (i Config) GetAdminProfile() (Profile, error) {
 
 	err := i.validateGetAdminProfileConfig()
 	if err != nil {
-		return "", fmt.Errorf("invalid parameters for admin profile: %w", err)
+		return Profile{}, fmt.Errorf("invalid parameters for admin profile: %w", err)
 	}
 
 	c, err := i.newIMSClient()
 	if err != nil {
-		return "", fmt.Errorf("error creating the IMS client: %w", err)
+		return Profile{}, fmt.Errorf("error creating the IMS client: %w", err)
 	}
 
 	profile, err := c.GetAdminProfile(&ims.GetAdminProfileRequest{
@@ -65,8 +65,8 @@ func (i Config) GetAdminProfile() (string, error) {
 		AuthSrc:      i.AuthSrc,
 	})
 	if err != nil {
-		return "", fmt.Errorf("error getting admin profile: %w", err)
+		return Profile{}, fmt.Errorf("error getting admin profile: %w", err)
 	}
 
-	return string(profile.Body), nil
+	return parseProfile(profile.Body)
 }
@@ -0,0 +1,353 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// AgentSocketEnv is the environment variable holding the path of the agent
+// socket, like SSH_AUTH_SOCK for ssh-agent.
+const AgentSocketEnv = "IMS_AGENT_SOCK"
+
+// Commands of the agent protocol. Each request and response is a JSON object
+// on a single line, several requests can be sent on the same connection.
+const (
+	AgentGet    = "get"
+	AgentStatus = "status"
+)
+
+const (
+	// agentRetryInterval is the delay before retrying a failed refresh.
+	agentRetryInterval = time.Minute
+	// agentIOTimeout bounds the time a client can keep a connection idle.
+	agentIOTimeout = 10 * time.Second
+)
+
+// AgentRequest is a request sent to the agent.
+type AgentRequest struct {
+	Command string `json:"command"`
+}
+
+// AgentResponse is the answer of the agent. The access token is only
+// included in the response to the get command.
+type AgentResponse struct {
+	AccessToken string   `json:"access_token,omitempty"`
+	ExpiresAt   string   `json:"expires_at,omitempty"`
+	Flow        string   `json:"flow,omitempty"`
+	ClientID    string   `json:"client_id,omitempty"`
+	Scopes      []string `json:"scopes,omitempty"`
+	Refreshable bool     `json:"refreshable,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// Agent holds the tokens negotiated by an authorization flow in memory and
+// serves the access token to local processes, refreshing it before it
+// expires.
+type Agent struct {
+	config        Config
+	flow          string
+	refreshBefore time.Duration
+
+	mu   sync.Mutex
+	info AuthorizationInfo
+}
+
+// AgentSocketPath returns the path of the agent socket: the value of
+// IMS_AGENT_SOCK, or agent.sock in the imscli configuration directory.
+func AgentSocketPath() (string, error) {
+	if path := os.Getenv(AgentSocketEnv); path != "" {
+		return path, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find configuration directory: %w", err)
+	}
+	return filepath.Join(configDir, "imscli", "agent.sock"), nil
+}
+
+// NewAgent negotiates the initial token with the given flow. The token is
+// refreshed the given duration before it expires.
+func (i Config) NewAgent(flow string, refreshBefore time.Duration) (*Agent, error) {
+	info, err := i.Authorize(flow)
+	if err != nil {
+		return nil, err
+	}
+	return &Agent{config: i, flow: flow, refreshBefore: refreshBefore, info: info}, nil
+}
+
+// ListenAgent creates the agent socket, accessible only by the current user.
+// A socket left behind by an agent that is not running anymore is replaced.
+// The socket is created in a private directory and restricted before being
+// moved to its path, so other users cannot connect in between.
+func ListenAgent(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("unable to create the socket directory: %w", err)
+	}
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("an agent is already listening at %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("unable to remove the stale socket %s: %w", path, err)
+		}
+	}
+
+	// MkdirTemp creates the directory with the 0700 permissions.
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".imscli-agent-")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the socket directory: %w", err)
+	}
+	defer func() { _ = os.Remove(dir) }()
+	tmp := filepath.Join(dir, "agent.sock")
+	l, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen at %s: %w", path, err)
+	}
+	// The socket is removed by agentListener.Close, at its final path.
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(tmp, 0o600); err != nil {
+		_ = l.Close()
+		_ = os.Remove(tmp)
+		return nil, fmt.Errorf("unable to restrict the permissions of %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = l.Close()
+		_ = os.Remove(tmp)
+		return nil, fmt.Errorf("unable to move the socket to %s: %w", path, err)
+	}
+	return &agentListener{Listener: l, path: path}, nil
+}
+
+// agentListener removes the agent socket when closed.
+type agentListener struct {
+	net.Listener
+	path string
+}
+
+func (l *agentListener) Close() error {
+	err := l.Listener.Close()
+	_ = os.Remove(l.path)
+	return err
+}
+
+// Serve answers the requests received on the listener and keeps the token
+// fresh until the context is canceled. The listener is closed on return.
+func (a *Agent) Serve(ctx context.Context, l net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		_ = l.Close()
+	}()
+	go a.refreshLoop(ctx)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("error accepting agent connection: %w", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.handle(conn)
+		}()
+	}
+}
+
+func (a *Agent) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	scanner := bufio.NewScanner(conn)
+	enc := json.NewEncoder(conn)
+	for {
+		_ = conn.SetDeadline(time.Now().Add(agentIOTimeout))
+		if !scanner.Scan() {
+			return
+		}
+		var req AgentRequest
+		var resp AgentResponse
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp.Error = fmt.Sprintf("malformed request: %v", err)
+		} else {
+			resp = a.answer(req)
+		}
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+func (a *Agent) answer(req AgentRequest) AgentResponse {
+	a.mu.Lock()
+	info := a.info
+	a.mu.Unlock()
+
+	resp := AgentResponse{
+		Flow:        a.flow,
+		ClientID:    a.config.ClientID,
+		Scopes:      info.Scopes,
+		Refreshable: info.RefreshToken != "",
+	}
+	if len(resp.Scopes) == 0 {
+		resp.Scopes = a.config.Scopes
+	}
+	if !info.ExpiresAt.IsZero() {
+		resp.ExpiresAt = info.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	switch req.Command {
+	case AgentStatus:
+	case AgentGet:
+		if !info.ExpiresAt.IsZero() && !time.Now().Before(info.ExpiresAt) {
+			resp.Error = "the token expired and could not be refreshed"
+			break
+		}
+		resp.AccessToken = info.AccessToken
+	default:
+		resp = AgentResponse{Error: fmt.Sprintf("unknown command %q", req.Command)}
+	}
+	return resp
+}
+
+// refreshLoop refreshes the token before it expires, retrying periodically
+// when IMS cannot be reached. A refreshed token expiring within refreshBefore
+// is refreshed again after agentRetryInterval, not right away, to avoid a loop
+// of requests to IMS.
+func (a *Agent) refreshLoop(ctx context.Context) {
+	for refreshed := false; ; refreshed = true {
+		a.mu.Lock()
+		expiresAt := a.info.ExpiresAt
+		a.mu.Unlock()
+		if expiresAt.IsZero() {
+			log.Println("Unable to find the token expiration, the token will not be refreshed.")
+			return
+		}
+
+		wait := time.Until(expiresAt) - a.refreshBefore
+		if refreshed && wait < agentRetryInterval {
+			log.Printf("The token expires in %s, not more than --refreshBefore %s, refreshing it in %s.",
+				time.Until(expiresAt).Round(time.Second), a.refreshBefore, agentRetryInterval)
+			wait = agentRetryInterval
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		for {
+			err := a.refresh()
+			if err == nil {
+				break
+			}
+			log.Printf("Unable to refresh the token, retrying in %s: %v", agentRetryInterval, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(agentRetryInterval):
+			}
+		}
+	}
+}
+
+// refresh uses the refresh token when there is one, and negotiates a new
+// token with the flow otherwise, or when the refresh token is rejected. Public
+// clients have no client secret to refresh their token with, they always
+// negotiate a new one.
+func (a *Agent) refresh() error {
+	a.mu.Lock()
+	current := a.info
+	a.mu.Unlock()
+
+	var info AuthorizationInfo
+	var err error
+	if current.RefreshToken != "" && a.config.ClientSecret != "" {
+		if info, err = a.refreshToken(current); err != nil {
+			log.Printf("Unable to use the refresh token, authorizing again: %v", err)
+		}
+	}
+	if info.AccessToken == "" {
+		config := a.config
+		config.NoCache = true
+		if info, err = config.Authorize(a.flow); err != nil {
+			return err
+		}
+	}
+
+	if a.config.Verbose {
+		log.Printf("Token refreshed, it expires at %s.", info.ExpiresAt.Format(time.RFC3339))
+	}
+	a.mu.Lock()
+	a.info = info
+	a.mu.Unlock()
+	return nil
+}
+
+// refreshToken exchanges the refresh token of the current tokens for new ones.
+func (a *Agent) refreshToken(current AuthorizationInfo) (AuthorizationInfo, error) {
+	config := a.config
+	config.RefreshToken = current.RefreshToken
+	r, err := config.Refresh()
+	if err != nil {
+		return AuthorizationInfo{}, err
+	}
+	info := AuthorizationInfo{
+		AccessToken:  r.AccessToken,
+		RefreshToken: r.RefreshToken,
+		IDToken:      current.IDToken,
+		ExpiresAt:    tokenExpiry(r.AccessToken, r.ExpiresIn, time.Now()),
+		Scopes:       current.Scopes,
+	}
+	if info.RefreshToken == "" {
+		info.RefreshToken = current.RefreshToken
+	}
+	return info, nil
+}
+
+// RequestAgent sends a command to the agent listening at the given socket.
+func RequestAgent(path, command string) (AgentResponse, error) {
+	conn, err := net.DialTimeout("unix", path, agentIOTimeout)
+	if err != nil {
+		return AgentResponse{}, fmt.Errorf("unable to connect to the agent at %s: %w", path, err)
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(agentIOTimeout))
+
+	if err := json.NewEncoder(conn).Encode(AgentRequest{Command: command}); err != nil {
+		return AgentResponse{}, fmt.Errorf("error sending the request to the agent: %w", err)
+	}
+	var resp AgentResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return AgentResponse{}, fmt.Errorf("error reading the response of the agent: %w", err)
+	}
+	if resp.Error != "" {
+		return resp, errors.New(resp.Error)
+	}
+	return resp, nil
+}
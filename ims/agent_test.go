@@ -0,0 +1,196 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adobe/imscli/mockims"
+)
+
+func TestAgent(t *testing.T) {
+	withTempConfigDir(t)
+	s, err := mockims.New(mockims.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+
+	// Get a refresh token through the service flow code.
+	code, err := s.IssueServiceCode([]string{"openid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.PostForm(srv.URL+"/ims/token/v2", url.Values{"grant_type": {"authorization_code"},
+		"client_id": {"cid"}, "client_secret": {"secret"}, "code": {code}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tokens struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&tokens)
+	_ = res.Body.Close()
+	if err != nil || tokens.RefreshToken == "" {
+		t.Fatalf("no refresh token issued: %v", err)
+	}
+
+	// The token expires right away, so it is refreshed as soon as the agent starts.
+	agent := &Agent{
+		config:        Config{URL: srv.URL, ClientID: "cid", ClientSecret: "secret", Timeout: 5},
+		flow:          "service",
+		refreshBefore: time.Minute,
+		info:          AuthorizationInfo{AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken, ExpiresAt: time.Now()},
+	}
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	l, err := ListenAgent(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ListenAgent(socket); err == nil || !strings.Contains(err.Error(), "already listening") {
+		t.Errorf("second agent: error = %v, want already listening", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- agent.Serve(ctx, l) }()
+
+	var got AgentResponse
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if got, err = RequestAgent(socket, AgentGet); err == nil && got.AccessToken != tokens.AccessToken {
+			break
+		}
+	}
+	if err != nil || got.AccessToken == "" || got.AccessToken == tokens.AccessToken {
+		t.Fatalf("get: %+v, err = %v, want a refreshed token", got, err)
+	}
+	info, err := Config{URL: srv.URL, ClientID: "cid", AccessToken: got.AccessToken, Timeout: 5}.ValidateToken()
+	if err != nil || !info.Valid {
+		t.Errorf("the refreshed token is not valid: %v", err)
+	}
+
+	status, err := RequestAgent(socket, AgentStatus)
+	if err != nil || status.AccessToken != "" || !status.Refreshable || status.Flow != "service" {
+		t.Errorf("status: %+v, err = %v, want the token metadata without the token", status, err)
+	}
+	if _, err := RequestAgent(socket, "steal"); err == nil || !strings.Contains(err.Error(), "unknown command") {
+		t.Errorf("unknown command: error = %v", err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("serve: unexpected error: %v", err)
+	}
+	if _, err := RequestAgent(socket, AgentGet); err == nil {
+		t.Error("expected an error once the agent stopped")
+	}
+}
+
+func TestListenAgent_Permissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no permission bits")
+	}
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "agent.sock")
+	l, err := ListenAgent(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("socket mode = %04o, want 0600", info.Mode().Perm())
+	}
+	// Only the socket is left in the directory.
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("directory entries = %v, want only the socket", entries)
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("close: unexpected error: %v", err)
+	}
+	if _, err := os.Stat(socket); !os.IsNotExist(err) {
+		t.Errorf("the socket must be removed on close, stat error = %v", err)
+	}
+}
+
+func TestAgent_RefreshFallback(t *testing.T) {
+	withTempConfigDir(t)
+	s, err := mockims.New(mockims.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+
+	// A rejected refresh token falls back to a new authorization.
+	agent := &Agent{
+		config: Config{URL: srv.URL, ClientID: "cid", ClientSecret: "secret", Scopes: []string{"openid"}, Timeout: 5},
+		flow:   "client",
+		info:   AuthorizationInfo{AccessToken: "old", RefreshToken: "revoked", ExpiresAt: time.Now()},
+	}
+	if err := agent.refresh(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if agent.info.AccessToken == "old" || agent.info.ExpiresAt.IsZero() {
+		t.Errorf("info = %+v, want a new token and its expiration", agent.info)
+	}
+}
+
+func TestAgent_RefreshLoopShortLifetime(t *testing.T) {
+	withTempConfigDir(t)
+	s, err := mockims.New(mockims.Options{TokenLifetime: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		s.Handler().ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	// The tokens expire within refreshBefore: the expired token is refreshed
+	// once, the new one is not refreshed right away.
+	agent := &Agent{
+		config:        Config{URL: srv.URL, ClientID: "cid", ClientSecret: "secret", Scopes: []string{"openid"}, Timeout: 5},
+		flow:          "client",
+		refreshBefore: 5 * time.Minute,
+		info:          AuthorizationInfo{AccessToken: "old", ExpiresAt: time.Now()},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		agent.refreshLoop(ctx)
+		close(done)
+	}()
+	time.Sleep(500 * time.Millisecond)
+	cancel()
+	<-done
+
+	if n := requests.Load(); n != 1 {
+		t.Errorf("%d requests to IMS, want 1", n)
+	}
+}
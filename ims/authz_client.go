@@ -12,6 +12,7 @@ package ims
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/adobe/ims-go/ims"
 )
@@ -40,22 +41,24 @@ func (i Config) AuthorizeClientCredentials() (string, error) {
 		return "", fmt.Errorf("invalid parameters for client credentials authorization: %w", err)
 	}
 
-	c, err := i.newIMSClient()
-	if err != nil {
-		return "", fmt.Errorf("error creating the IMS client: %w", err)
-	}
+	return i.cachedAuthorization("clientCredentials", func() (string, time.Duration, error) {
+		c, err := i.newIMSClient()
+		if err != nil {
+			return "", 0, fmt.Errorf("error creating the IMS client: %w", err)
+		}
 
-	r, err := c.Token(&ims.TokenRequest{
-		ClientID:     i.ClientID,
-		ClientSecret: i.ClientSecret,
-		Scope:        i.Scopes,
-		GrantType:    "client_credentials",
-		OrgID:        i.Organization,
-		Resource:     i.Resource,
-	})
-	if err != nil {
-		return "", fmt.Errorf("error requesting token: %w", err)
-	}
+		r, err := c.Token(&ims.TokenRequest{
+			ClientID:     i.ClientID,
+			ClientSecret: i.ClientSecret,
+			Scope:        i.Scopes,
+			GrantType:    "client_credentials",
+			OrgID:        i.Organization,
+			Resource:     i.Resource,
+		})
+		if err != nil {
+			return "", 0, fmt.Errorf("error requesting token: %w", err)
+		}
 
-	return r.AccessToken, nil
+		return r.AccessToken, r.ExpiresIn, nil
+	})
 }
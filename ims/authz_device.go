@@ -0,0 +1,235 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+// OAuth 2.0 Device Authorization Grant (RFC 8628). ims-go has no support for
+// this grant, so the requests are performed directly with the HTTP client.
+
+package ims
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	deviceAuthorizationPath = "/ims/device/authorize/v1"
+	deviceTokenPath         = "/ims/token/v3"
+	deviceCodeGrantType     = "urn:ietf:params:oauth:grant-type:device_code"
+)
+
+var (
+	// devicePollInterval is the polling interval used when the device
+	// authorization response does not specify one (RFC 8628, section 3.2).
+	devicePollInterval = 5 * time.Second
+
+	// deviceSlowDownStep is added to the polling interval every time the
+	// token endpoint answers slow_down (RFC 8628, section 3.5).
+	deviceSlowDownStep = 5 * time.Second
+)
+
+// deviceAuthorization is the response of the device authorization endpoint.
+type deviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// deviceTokenResponse holds both the successful and the error responses of
+// the token endpoint while polling.
+type deviceTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// validateAuthorizeDeviceConfig checks that the configuration has valid
+// parameters for the device authorization grant.
+func (i Config) validateAuthorizeDeviceConfig() error {
+	switch {
+	case i.URL == "":
+		return fmt.Errorf("missing IMS base URL parameter")
+	case !validateURL(i.URL):
+		return fmt.Errorf("unable to parse URL parameter")
+	case len(i.Scopes) == 0 || i.Scopes[0] == "":
+		return fmt.Errorf("missing scopes parameter")
+	case i.ClientID == "":
+		return fmt.Errorf("missing client id parameter")
+	}
+	log.Println("all needed parameters verified not empty")
+	return nil
+}
+
+// AuthorizeDevice performs the OAuth 2.0 Device Authorization Grant. The
+// verification URI and the user code are printed to stderr, so the user can
+// complete the login from any other device with a browser, while the CLI polls
+// the token endpoint.
+func (i Config) AuthorizeDevice() (string, error) {
+	if err := i.validateAuthorizeDeviceConfig(); err != nil {
+		return "", fmt.Errorf("invalid parameters for device authorization: %w", err)
+	}
+
+	return i.cachedAuthorization("device", i.authorizeDevice)
+}
+
+func (i Config) authorizeDevice() (string, time.Duration, error) {
+	client, err := i.httpClient()
+	if err != nil {
+		return "", 0, fmt.Errorf("error creating the HTTP client: %w", err)
+	}
+
+	auth, err := i.requestDeviceCode(client)
+	if err != nil {
+		return "", 0, err
+	}
+
+	fmt.Fprintf(os.Stderr, "To authorize imscli, visit %s and enter the code: %s\n", auth.VerificationURI, auth.UserCode)
+	if auth.VerificationURIComplete != "" {
+		fmt.Fprintf(os.Stderr, "Alternatively, visit %s\n", auth.VerificationURIComplete)
+	}
+
+	timeout := authTimeout
+	if expiresIn := time.Duration(auth.ExpiresIn) * time.Second; expiresIn > 0 && expiresIn < timeout {
+		timeout = expiresIn
+	}
+	deadline := time.Now().Add(timeout)
+
+	interval := devicePollInterval
+	if auth.Interval > 0 {
+		interval = time.Duration(auth.Interval) * time.Second
+	}
+
+	for {
+		if time.Now().Add(interval).After(deadline) {
+			fmt.Fprintf(os.Stderr, "Timeout reached waiting for the user to finish the authentication ...\n")
+			return "", 0, fmt.Errorf("user timed out")
+		}
+		time.Sleep(interval)
+
+		resp, err := i.pollDeviceToken(client, auth.DeviceCode)
+		if err != nil {
+			return "", 0, err
+		}
+
+		switch resp.Error {
+		case "":
+			log.Println("The user completed the device authorization.")
+			return resp.AccessToken, time.Duration(resp.ExpiresIn) * time.Second, nil
+		case "authorization_pending":
+			log.Println("Waiting for the user to complete the device authorization.")
+		case "slow_down":
+			interval += deviceSlowDownStep
+			log.Printf("IMS requested a slower polling, polling every %s.", interval)
+		case "access_denied":
+			return "", 0, fmt.Errorf("the user denied the authorization request")
+		case "expired_token":
+			return "", 0, fmt.Errorf("the device code expired before the user completed the authorization")
+		default:
+			return "", 0, fmt.Errorf("error polling the token endpoint: %s: %s", resp.Error, resp.ErrorDescription)
+		}
+	}
+}
+
+// requestDeviceCode starts the device authorization (RFC 8628, section 3.1).
+func (i Config) requestDeviceCode(client *http.Client) (*deviceAuthorization, error) {
+	data := url.Values{}
+	data.Set("client_id", i.ClientID)
+	if i.ClientSecret != "" {
+		data.Set("client_secret", i.ClientSecret)
+	}
+	data.Set("scope", strings.Join(i.Scopes, ","))
+	for _, res := range i.Resource {
+		data.Add("resource", res)
+	}
+
+	body, status, err := postForm(client, strings.TrimRight(i.URL, "/")+deviceAuthorizationPath, data)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting the device code: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("error requesting the device code: statusCode=%d, body=%s", status, body)
+	}
+
+	var auth deviceAuthorization
+	if err := json.Unmarshal(body, &auth); err != nil {
+		return nil, fmt.Errorf("error decoding the device authorization response: %w", err)
+	}
+	switch {
+	case auth.DeviceCode == "":
+		return nil, fmt.Errorf("missing device_code in the device authorization response")
+	case auth.UserCode == "":
+		return nil, fmt.Errorf("missing user_code in the device authorization response")
+	case auth.VerificationURI == "":
+		return nil, fmt.Errorf("missing verification_uri in the device authorization response")
+	}
+	return &auth, nil
+}
+
+// pollDeviceToken performs a single access token request (RFC 8628,
+// section 3.4). Error responses defined by the RFC are returned in the
+// response, not as an error, so the caller can keep polling.
+func (i Config) pollDeviceToken(client *http.Client, deviceCode string) (*deviceTokenResponse, error) {
+	data := url.Values{}
+	data.Set("grant_type", deviceCodeGrantType)
+	data.Set("device_code", deviceCode)
+	data.Set("client_id", i.ClientID)
+	if i.ClientSecret != "" {
+		data.Set("client_secret", i.ClientSecret)
+	}
+
+	body, status, err := postForm(client, strings.TrimRight(i.URL, "/")+deviceTokenPath, data)
+	if err != nil {
+		return nil, fmt.Errorf("error polling the token endpoint: %w", err)
+	}
+
+	var resp deviceTokenResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("error polling the token endpoint: statusCode=%d, body=%s", status, body)
+	}
+	if status == http.StatusOK && resp.AccessToken == "" {
+		return nil, fmt.Errorf("missing access_token in the token response")
+	}
+	if status != http.StatusOK && resp.Error == "" {
+		return nil, fmt.Errorf("error polling the token endpoint: statusCode=%d, body=%s", status, body)
+	}
+	return &resp, nil
+}
+
+// postForm sends a form-encoded POST request and returns the response body
+// and status code.
+func postForm(client *http.Client, endpoint string, data url.Values) ([]byte, int, error) {
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, 0, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("perform request: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read response body: %w", err)
+	}
+	return body, res.StatusCode, nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestValidateAuthorizeDeviceConfig(t *testing.T) {
+	valid := Config{URL: "https://ims.example.com", ClientID: "c", Scopes: []string{"openid"}}
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{name: "valid", config: valid, wantErr: ""},
+		{name: "missing URL", config: withField(valid, func(c *Config) { c.URL = "" }), wantErr: "missing IMS base URL"},
+		{name: "invalid URL", config: withField(valid, func(c *Config) { c.URL = "not a url" }), wantErr: "unable to parse URL"},
+		{name: "missing scopes", config: withField(valid, func(c *Config) { c.Scopes = nil }), wantErr: "missing scopes"},
+		{name: "empty scope", config: withField(valid, func(c *Config) { c.Scopes = []string{""} }), wantErr: "missing scopes"},
+		{name: "missing client ID", config: withField(valid, func(c *Config) { c.ClientID = "" }), wantErr: "missing client id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertError(t, tt.config.validateAuthorizeDeviceConfig(), tt.wantErr)
+		})
+	}
+}
+
+// newDeviceServer serves the device authorization endpoint and answers the
+// token polls with the given responses, in order. The last response is
+// repeated once the list is exhausted.
+func newDeviceServer(t *testing.T, polls ...string) (*httptest.Server, *deviceRequests) {
+	t.Helper()
+	reqs := &deviceRequests{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+deviceAuthorizationPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		reqs.record(r.PostForm.Get("scope"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"device_code":"dc","user_code":"ABCD-EFGH",`+
+			`"verification_uri":"https://ims.example.com/device","expires_in":600}`)
+	})
+	mux.HandleFunc("POST "+deviceTokenPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		n := reqs.record(r.PostForm.Get("grant_type") + " " + r.PostForm.Get("device_code"))
+		body := polls[min(n-2, len(polls)-1)]
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(body, `"error"`) {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		_, _ = io.WriteString(w, body)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, reqs
+}
+
+type deviceRequests struct {
+	mu     sync.Mutex
+	values []string
+}
+
+func (d *deviceRequests) record(v string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.values = append(d.values, v)
+	return len(d.values)
+}
+
+func fastDevicePolling(t *testing.T) {
+	t.Helper()
+	interval, step := devicePollInterval, deviceSlowDownStep
+	devicePollInterval, deviceSlowDownStep = time.Millisecond, time.Millisecond
+	t.Cleanup(func() { devicePollInterval, deviceSlowDownStep = interval, step })
+}
+
+func TestAuthorizeDevice(t *testing.T) {
+	fastDevicePolling(t)
+	withTempConfigDir(t)
+
+	srv, reqs := newDeviceServer(t,
+		`{"error":"authorization_pending"}`,
+		`{"error":"slow_down"}`,
+		`{"access_token":"device-at","expires_in":3600}`,
+	)
+
+	c := Config{URL: srv.URL, ClientID: "cid", Scopes: []string{"openid", "AdobeID"}, NoCache: true}
+	token, err := c.AuthorizeDevice()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "device-at" {
+		t.Errorf("token = %q, want %q", token, "device-at")
+	}
+
+	want := []string{"openid,AdobeID", deviceCodeGrantType + " dc", deviceCodeGrantType + " dc", deviceCodeGrantType + " dc"}
+	if len(reqs.values) != len(want) {
+		t.Fatalf("got requests %q, want %q", reqs.values, want)
+	}
+	for i := range want {
+		if reqs.values[i] != want[i] {
+			t.Errorf("request %d = %q, want %q", i, reqs.values[i], want[i])
+		}
+	}
+}
+
+func TestAuthorizeDevice_Errors(t *testing.T) {
+	fastDevicePolling(t)
+	withTempConfigDir(t)
+
+	tests := []struct {
+		name    string
+		poll    string
+		wantErr string
+	}{
+		{name: "denied", poll: `{"error":"access_denied"}`, wantErr: "denied the authorization"},
+		{name: "expired", poll: `{"error":"expired_token"}`, wantErr: "device code expired"},
+		{name: "unknown error", poll: `{"error":"invalid_client","error_description":"bad client"}`, wantErr: "invalid_client: bad client"},
+		{name: "missing token", poll: `{"expires_in":3600}`, wantErr: "missing access_token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _ := newDeviceServer(t, tt.poll)
+			c := Config{URL: srv.URL, ClientID: "cid", Scopes: []string{"openid"}, NoCache: true}
+			_, err := c.AuthorizeDevice()
+			assertError(t, err, tt.wantErr)
+		})
+	}
+}
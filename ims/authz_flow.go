@@ -0,0 +1,53 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AuthorizationFlows lists the flows accepted by Authorize, named after the
+// authorize subcommands.
+var AuthorizationFlows = []string{"client", "service", "jwt", "user", "pkce", "implicit", "device"}
+
+// Authorize negotiates an access token with the given flow, for the commands
+// that need a token without caring how it is obtained. The expiration is read
+// from the token claims for the flows that only return the access token.
+func (i Config) Authorize(flow string) (AuthorizationInfo, error) {
+	var token string
+	var err error
+	switch flow {
+	case "user":
+		return i.AuthorizeUser()
+	case "pkce":
+		return i.AuthorizeUserPKCE()
+	case "client":
+		token, err = i.AuthorizeClientCredentials()
+	case "service":
+		token, err = i.AuthorizeService()
+	case "jwt":
+		var info TokenInfo
+		info, err = i.AuthorizeJWTExchange()
+		token = info.AccessToken
+	case "implicit":
+		token, err = i.AuthorizeImplicit()
+	case "device":
+		token, err = i.AuthorizeDevice()
+	default:
+		return AuthorizationInfo{}, fmt.Errorf("unknown authorization flow %q, supported flows are %s", flow,
+			strings.Join(AuthorizationFlows, ", "))
+	}
+	if err != nil {
+		return AuthorizationInfo{}, err
+	}
+	return AuthorizationInfo{AccessToken: token, ExpiresAt: claimsExpiry(token)}, nil
+}
@@ -71,14 +71,20 @@ func (i Config) AuthorizeImplicit() (string, error) {
 		return "", fmt.Errorf("invalid parameters for implicit authorization: %w", err)
 	}
 
+	return i.cachedAuthorization("implicit", i.loginImplicit)
+}
+
+// loginImplicit runs the local capture server and waits for the user to
+// complete the authorization in the browser.
+func (i Config) loginImplicit() (string, time.Duration, error) {
 	c, err := i.newIMSClient()
 	if err != nil {
-		return "", fmt.Errorf("error creating the IMS client: %w", err)
+		return "", 0, fmt.Errorf("error creating the IMS client: %w", err)
 	}
 
 	state, err := randomState()
 	if err != nil {
-		return "", fmt.Errorf("generate state: %w", err)
+		return "", 0, fmt.Errorf("generate state: %w", err)
 	}
 
 	authURL, err := c.AuthorizeURL(&ims.AuthorizeURLConfig{
@@ -90,12 +96,12 @@ func (i Config) AuthorizeImplicit() (string, error) {
 		Resource:    i.Resource,
 	})
 	if err != nil {
-		return "", fmt.Errorf("build authorize URL: %w", err)
+		return "", 0, fmt.Errorf("build authorize URL: %w", err)
 	}
 
 	srv, err := startCaptureServer(state, i.Port)
 	if err != nil {
-		return "", err
+		return "", 0, err
 	}
 	log.Println("Local server successfully launched and contacted.")
 
@@ -122,15 +128,15 @@ func (i Config) AuthorizeImplicit() (string, error) {
 	defer cancel()
 
 	if err := srv.server.Shutdown(shutdownCtx); err != nil {
-		return "", fmt.Errorf("error shutting down the local server: %w", err)
+		return "", 0, fmt.Errorf("error shutting down the local server: %w", err)
 	}
 	log.Println("Local server shut down ...")
 
 	if serr != nil {
-		return "", fmt.Errorf("error in implicit authorization: %w", serr)
+		return "", 0, fmt.Errorf("error in implicit authorization: %w", serr)
 	}
 
-	return resp.AccessToken, nil
+	return resp.AccessToken, 0, nil
 }
 
 // captureServer is the local HTTP listener that receives the access token
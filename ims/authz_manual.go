@@ -0,0 +1,133 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+// The manual mode talks to the user through these, replaced in tests.
+var (
+	manualInput  io.Reader = os.Stdin
+	manualPrompt io.Writer = os.Stderr
+)
+
+// loginUserManual performs the authorization code flow without a browser nor
+// a local server, for remote machines: the user opens the printed authorize
+// URL in any browser and pastes back the URL the browser was redirected to,
+// or just the code.
+func (i Config) loginUserManual(pkce bool) (*ims.TokenResponse, error) {
+	c, err := i.newIMSClient()
+	if err != nil {
+		return nil, fmt.Errorf("error creating the IMS client: %w", err)
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return nil, fmt.Errorf("generate state: %w", err)
+	}
+	var verifier string
+	if pkce {
+		if verifier, err = randomCodeVerifier(); err != nil {
+			return nil, err
+		}
+	}
+
+	redirectURI := fmt.Sprintf("http://localhost:%d", i.Port)
+	authURL, err := c.AuthorizeURL(&ims.AuthorizeURLConfig{
+		ClientID:     i.ClientID,
+		GrantType:    ims.GrantTypeCode,
+		Scope:        i.Scopes,
+		RedirectURI:  redirectURI,
+		State:        state,
+		CodeVerifier: verifier,
+		Resource:     i.Resource,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("build authorize URL: %w", err)
+	}
+
+	fmt.Fprintf(manualPrompt, "Open the following URL in a browser and log in:\n\n%s\n\n", authURL)
+	fmt.Fprintf(manualPrompt, "The browser is then redirected to %s, the page may fail to load.\n", redirectURI)
+	fmt.Fprintf(manualPrompt, "Paste the URL of that page, or the authorization code: ")
+
+	line, err := bufio.NewReader(manualInput).ReadString('\n')
+	if err != nil && line == "" {
+		return nil, fmt.Errorf("error reading the authorization response: %w", err)
+	}
+	code, err := parseAuthorizationResponse(line, state)
+	if err != nil {
+		return nil, fmt.Errorf("error negotiating the authorization code: %w", err)
+	}
+	log.Println("Authorization code received, exchanging it for a token.")
+
+	resp, err := c.Token(&ims.TokenRequest{
+		Code:         code,
+		ClientID:     i.ClientID,
+		ClientSecret: i.ClientSecret,
+		Scope:        i.Scopes,
+		CodeVerifier: verifier,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error negotiating the authorization code: obtaining access token: %w", err)
+	}
+	return resp, nil
+}
+
+// parseAuthorizationResponse extracts the code from the pasted redirect URL,
+// validating the state like the local server does, or accepts a bare code.
+func parseAuthorizationResponse(input, state string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", fmt.Errorf("empty authorization response")
+	}
+	if !strings.Contains(input, "?") && !strings.Contains(input, "=") {
+		return input, nil
+	}
+
+	_, query, _ := strings.Cut(input, "?")
+	q, err := url.ParseQuery(query)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse the redirect URL: %w", err)
+	}
+	if e := q.Get("error"); e != "" {
+		return "", fmt.Errorf("authorization error: %s: %s", e, q.Get("error_description"))
+	}
+	if subtle.ConstantTimeCompare([]byte(q.Get("state")), []byte(state)) != 1 {
+		return "", fmt.Errorf("state mismatch")
+	}
+	code := q.Get("code")
+	if code == "" {
+		return "", fmt.Errorf("missing code in the redirect URL")
+	}
+	return code, nil
+}
+
+// randomCodeVerifier generates the PKCE code verifier (RFC 7636, section
+// 4.1). Mirrors github.com/adobe/ims-go/login/server.go.
+func randomCodeVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate code verifier: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
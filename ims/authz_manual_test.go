@@ -0,0 +1,103 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adobe/imscli/mockims"
+)
+
+func TestParseAuthorizationResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{name: "redirect URL", input: "http://localhost:8888/?code=abc&state=s%2B1\n", want: "abc"},
+		{name: "query only", input: "?state=s%2B1&code=abc", want: "abc"},
+		{name: "bare code", input: "  eyJhbGciOi.abc  \n", want: "eyJhbGciOi.abc"},
+		{name: "empty", input: "\n", wantErr: "empty authorization response"},
+		{name: "state mismatch", input: "http://localhost:8888/?code=abc&state=other", wantErr: "state mismatch"},
+		{name: "missing state", input: "http://localhost:8888/?code=abc", wantErr: "state mismatch"},
+		{name: "missing code", input: "http://localhost:8888/?state=s%2B1", wantErr: "missing code"},
+		{
+			name:    "error",
+			input:   "http://localhost:8888/?error=access_denied&error_description=denied&state=s%2B1",
+			wantErr: "authorization error: access_denied: denied",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAuthorizationResponse(tt.input, "s+1")
+			assertError(t, err, tt.wantErr)
+			if got != tt.want {
+				t.Errorf("code = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizeUserPKCE_Manual(t *testing.T) {
+	withTempConfigDir(t)
+	s, err := mockims.New(mockims.Options{AutoLogin: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+
+	// Play the user: open the printed URL and paste the redirect URL back.
+	promptR, promptW := io.Pipe()
+	inputR, inputW := io.Pipe()
+	origPrompt, origInput := manualPrompt, manualInput
+	manualPrompt, manualInput = promptW, inputR
+	t.Cleanup(func() {
+		manualPrompt, manualInput = origPrompt, origInput
+		_ = promptR.Close()
+		_ = inputW.Close()
+	})
+	go func() {
+		scanner := bufio.NewScanner(promptR)
+		for scanner.Scan() {
+			if !strings.HasPrefix(scanner.Text(), srv.URL) {
+				continue
+			}
+			client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			}}
+			res, err := client.Get(scanner.Text())
+			if err != nil {
+				_ = inputW.CloseWithError(err)
+				return
+			}
+			_ = res.Body.Close()
+			// Keep draining the prompt while the redirect URL is pasted.
+			go func() { _, _ = io.WriteString(inputW, res.Header.Get("Location")+"\n") }()
+		}
+	}()
+
+	config := Config{URL: srv.URL, ClientID: "public", PublicClient: true, Organization: "org",
+		Scopes: []string{"openid"}, Port: 8888, NoBrowser: true, NoCache: true, Timeout: 5}
+	got, err := config.AuthorizeUserPKCE()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.AccessToken == "" || got.RefreshToken == "" {
+		t.Errorf("got %+v, want access and refresh tokens", got)
+	}
+}
@@ -12,6 +12,7 @@ package ims
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/adobe/ims-go/ims"
 )
@@ -40,20 +41,22 @@ func (i Config) AuthorizeService() (string, error) {
 		return "", fmt.Errorf("invalid parameters for service authorization: %w", err)
 	}
 
-	c, err := i.newIMSClient()
-	if err != nil {
-		return "", fmt.Errorf("error creating the IMS client: %w", err)
-	}
-
-	r, err := c.Token(&ims.TokenRequest{
-		ClientID:     i.ClientID,
-		ClientSecret: i.ClientSecret,
-		Code:         i.AuthorizationCode,
-		Resource:     i.Resource,
+	return i.cachedAuthorization("service", func() (string, time.Duration, error) {
+		c, err := i.newIMSClient()
+		if err != nil {
+			return "", 0, fmt.Errorf("error creating the IMS client: %w", err)
+		}
+
+		r, err := c.Token(&ims.TokenRequest{
+			ClientID:     i.ClientID,
+			ClientSecret: i.ClientSecret,
+			Code:         i.AuthorizationCode,
+			Resource:     i.Resource,
+		})
+		if err != nil {
+			return "", 0, fmt.Errorf("error requesting token: %w", err)
+		}
+
+		return r.AccessToken, r.ExpiresIn, nil
 	})
-	if err != nil {
-		return "", fmt.Errorf("error requesting token: %w", err)
-	}
-
-	return r.AccessToken, nil
 }
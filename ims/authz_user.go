@@ -12,11 +12,13 @@ package ims
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/adobe/ims-go/ims"
@@ -63,25 +65,70 @@ func (i Config) validateAuthorizeUserConfig() error {
 }
 
 // AuthorizeUser uses the standard OAuth2 authorization code grant flow.
-func (i Config) AuthorizeUser() (string, error) {
+func (i Config) AuthorizeUser() (AuthorizationInfo, error) {
 	return i.authorizeUser(false)
 }
 
 // AuthorizeUserPKCE uses the OAuth2 authorization code grant flow with PKCE.
-func (i Config) AuthorizeUserPKCE() (string, error) {
+func (i Config) AuthorizeUserPKCE() (AuthorizationInfo, error) {
 	return i.authorizeUser(true)
 }
 
-func (i Config) authorizeUser(pkce bool) (string, error) {
+func (i Config) authorizeUser(pkce bool) (AuthorizationInfo, error) {
 	// Perform parameter validation
 	err := i.validateAuthorizeUserConfig()
 	if err != nil {
-		return "", fmt.Errorf("invalid parameters for login user: %w", err)
+		return AuthorizationInfo{}, fmt.Errorf("invalid parameters for login user: %w", err)
 	}
 
+	flow := "user"
+	if pkce {
+		flow = "pkce"
+	}
+	login := i.loginUser
+	if i.NoBrowser {
+		login = i.loginUserManual
+	}
+	return i.cachedAuthorizationInfo(flow, func() (AuthorizationInfo, time.Duration, error) {
+		resp, err := login(pkce)
+		if err != nil {
+			return AuthorizationInfo{}, 0, err
+		}
+		return i.authorizationInfo(resp), resp.ExpiresIn, nil
+	})
+}
+
+// authorizationInfo extracts the fields that ims-go does not parse from the
+// raw token response. When IMS does not list the granted scopes, they are
+// the requested ones (RFC 6749, section 5.1).
+func (i Config) authorizationInfo(resp *ims.TokenResponse) AuthorizationInfo {
+	var payload struct {
+		IDToken string `json:"id_token"`
+		Scope   string `json:"scope"`
+	}
+	if err := json.Unmarshal(resp.Body, &payload); err != nil {
+		log.Printf("Unable to parse the token response: %v", err)
+	}
+
+	scopes := i.Scopes
+	if payload.Scope != "" {
+		scopes = strings.FieldsFunc(payload.Scope, func(r rune) bool { return r == ',' || r == ' ' })
+	}
+
+	return AuthorizationInfo{
+		AccessToken:  resp.AccessToken,
+		RefreshToken: resp.RefreshToken,
+		IDToken:      payload.IDToken,
+		Scopes:       scopes,
+	}
+}
+
+// loginUser runs the local login server and waits for the user to complete
+// the authorization in the browser.
+func (i Config) loginUser(pkce bool) (*ims.TokenResponse, error) {
 	c, err := i.newIMSClient()
 	if err != nil {
-		return "", fmt.Errorf("error creating the IMS client: %w", err)
+		return nil, fmt.Errorf("error creating the IMS client: %w", err)
 	}
 
 	server, err := login.NewServer(&login.ServerConfig{
@@ -106,12 +153,12 @@ func (i Config) authorizeUser(pkce bool) (string, error) {
 		}),
 	})
 	if err != nil {
-		return "", fmt.Errorf("create authorization server: %w", err)
+		return nil, fmt.Errorf("create authorization server: %w", err)
 	}
 
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", i.Port))
 	if err != nil {
-		return "", fmt.Errorf("unable to listen at port %d", i.Port)
+		return nil, fmt.Errorf("unable to listen at port %d", i.Port)
 	}
 	defer func() { _ = listener.Close() }()
 
@@ -162,14 +209,14 @@ func (i Config) authorizeUser(pkce bool) (string, error) {
 	defer cancel()
 
 	if err = server.Shutdown(shutdownCtx); err != nil {
-		return "", fmt.Errorf("error shutting down the local server: %w", err)
+		return nil, fmt.Errorf("error shutting down the local server: %w", err)
 	}
 	log.Println("Local server shut down ...")
 
 	if serr != nil {
-		return "", fmt.Errorf("error negotiating the authorization code: %w", serr)
+		return nil, fmt.Errorf("error negotiating the authorization code: %w", serr)
 	}
 	log.Println("No error from Authorization Code handler, server is successfully shut down.")
 
-	return resp.AccessToken, nil
+	return resp, nil
 }
@@ -0,0 +1,70 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/adobe/ims-go/ims"
+)
+
+func TestAuthorizationInfo(t *testing.T) {
+	requested := []string{"openid", "AdobeID"}
+	tests := []struct {
+		name       string
+		body       string
+		wantID     string
+		wantScopes []string
+	}{
+		{
+			name:       "id token and comma-separated scopes",
+			body:       `{"access_token":"at","id_token":"idt","scope":"openid,AdobeID,session"}`,
+			wantID:     "idt",
+			wantScopes: []string{"openid", "AdobeID", "session"},
+		},
+		{
+			name:       "space-separated scopes",
+			body:       `{"access_token":"at","scope":"openid AdobeID"}`,
+			wantScopes: []string{"openid", "AdobeID"},
+		},
+		{
+			name:       "no scope falls back to requested scopes",
+			body:       `{"access_token":"at"}`,
+			wantScopes: requested,
+		},
+		{
+			name:       "unparsable body",
+			body:       `not json`,
+			wantScopes: requested,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{Scopes: requested}
+			resp := &ims.TokenResponse{
+				Response:     ims.Response{Body: []byte(tt.body)},
+				AccessToken:  "at",
+				RefreshToken: "rt",
+			}
+			got := c.authorizationInfo(resp)
+			if got.AccessToken != "at" || got.RefreshToken != "rt" {
+				t.Errorf("tokens = %q/%q, want at/rt", got.AccessToken, got.RefreshToken)
+			}
+			if got.IDToken != tt.wantID {
+				t.Errorf("IDToken = %q, want %q", got.IDToken, tt.wantID)
+			}
+			if !slices.Equal(got.Scopes, tt.wantScopes) {
+				t.Errorf("Scopes = %q, want %q", got.Scopes, tt.wantScopes)
+			}
+		})
+	}
+}
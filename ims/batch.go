@@ -0,0 +1,265 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Token types of the batch files, named after the token flags.
+const (
+	BatchAccessToken       = "accessToken"
+	BatchRefreshToken      = "refreshToken"
+	BatchDeviceToken       = "deviceToken"
+	BatchServiceToken      = "serviceToken"
+	BatchAuthorizationCode = "authorizationCode"
+)
+
+// maxBatchLine bounds the length of a line of a batch file.
+const maxBatchLine = 1024 * 1024
+
+// BatchToken is a token read from a batch file.
+type BatchToken struct {
+	Line  int
+	Token string
+	Type  string
+}
+
+// BatchResult is the outcome of the validation or invalidation of a token of
+// a batch. The token is abbreviated so the report can be shared safely.
+type BatchResult struct {
+	Line    int    `json:"line"`
+	Token   string `json:"token"`
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Success bool   `json:"success"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// BatchReport holds the results of a batch, in the order of the file.
+type BatchReport struct {
+	Total   int           `json:"total"`
+	Failed  int           `json:"failed"`
+	Results []BatchResult `json:"results"`
+}
+
+// ParseBatchTokens reads one token per line. A line is either the bare token,
+// of the default type, or a JSON object with the token and optionally its
+// type, e.g. {"token": "...", "type": "refreshToken"}. Empty lines and lines
+// starting with # are ignored.
+func ParseBatchTokens(r io.Reader, defaultType string) ([]BatchToken, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxBatchLine)
+
+	var tokens []BatchToken
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		t := BatchToken{Line: n, Token: line, Type: defaultType}
+		if strings.HasPrefix(line, "{") {
+			var entry struct {
+				Token string `json:"token"`
+				Type  string `json:"type"`
+			}
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				return nil, fmt.Errorf("line %d: malformed JSON: %w", n, err)
+			}
+			if entry.Token == "" {
+				return nil, fmt.Errorf("line %d: missing token", n)
+			}
+			t.Token = entry.Token
+			if entry.Type != "" {
+				t.Type = entry.Type
+			}
+		}
+		typ, ok := batchTokenType(t.Type)
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown token type %q", n, t.Type)
+		}
+		t.Type = typ
+		tokens = append(tokens, t)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading the tokens: %w", err)
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no token found")
+	}
+	return tokens, nil
+}
+
+// ReadBatchTokens parses the batch file at the given path, or stdin when the
+// path is "-".
+func ReadBatchTokens(path string, stdin io.Reader, defaultType string) ([]BatchToken, error) {
+	if path == "-" {
+		return ParseBatchTokens(stdin, defaultType)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the token file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return ParseBatchTokens(f, defaultType)
+}
+
+// batchTokenType returns the canonical name of a token type, given either as
+// a flag name (accessToken) or as an IMS type (access_token).
+func batchTokenType(name string) (string, bool) {
+	switch strings.ToLower(strings.ReplaceAll(name, "_", "")) {
+	case "accesstoken":
+		return BatchAccessToken, true
+	case "refreshtoken":
+		return BatchRefreshToken, true
+	case "devicetoken":
+		return BatchDeviceToken, true
+	case "servicetoken":
+		return BatchServiceToken, true
+	case "authorizationcode":
+		return BatchAuthorizationCode, true
+	default:
+		return "", false
+	}
+}
+
+// withToken returns a copy of the configuration holding only the given token.
+func (i Config) withToken(t BatchToken) Config {
+	i.AccessToken, i.RefreshToken, i.DeviceToken, i.ServiceToken, i.AuthorizationCode = "", "", "", "", ""
+	switch t.Type {
+	case BatchAccessToken:
+		i.AccessToken = t.Token
+	case BatchRefreshToken:
+		i.RefreshToken = t.Token
+	case BatchDeviceToken:
+		i.DeviceToken = t.Token
+	case BatchServiceToken:
+		i.ServiceToken = t.Token
+	case BatchAuthorizationCode:
+		i.AuthorizationCode = t.Token
+	}
+	return i
+}
+
+// validateBatchConfig checks the parameters shared by the tokens of a batch.
+func (i Config) validateBatchConfig(concurrency int) error {
+	switch {
+	case i.ClientID == "":
+		return fmt.Errorf("missing clientID parameter")
+	case i.URL == "":
+		return fmt.Errorf("missing IMS base URL parameter")
+	case !validateURL(i.URL):
+		return fmt.Errorf("invalid IMS base URL parameter")
+	case concurrency < 1:
+		return fmt.Errorf("invalid concurrency parameter, it must be at least 1")
+	default:
+		return nil
+	}
+}
+
+// ValidateTokens validates the tokens with at most concurrency simultaneous
+// calls to the IMS API. Invalid tokens are reported as failures.
+func (i Config) ValidateTokens(tokens []BatchToken, concurrency int) (BatchReport, error) {
+	if err := i.validateBatchConfig(concurrency); err != nil {
+		return BatchReport{}, fmt.Errorf("invalid parameters for token validation: %w", err)
+	}
+	c, err := i.newIMSClient()
+	if err != nil {
+		return BatchReport{}, fmt.Errorf("error creating the IMS client: %w", err)
+	}
+
+	return runBatch(tokens, concurrency, func(t BatchToken) BatchResult {
+		config := i.withToken(t)
+		err := config.validateValidateTokenConfig()
+		if err != nil {
+			return BatchResult{Status: "error", Reason: err.Error()}
+		}
+		info, err := config.validateToken(c)
+		switch {
+		case err != nil:
+			return BatchResult{Status: "error", Reason: err.Error()}
+		case !info.Valid:
+			return BatchResult{Status: "invalid", Reason: strings.TrimSpace(info.Info)}
+		default:
+			return BatchResult{Status: "valid", Success: true}
+		}
+	}), nil
+}
+
+// InvalidateTokens invalidates the tokens with at most concurrency
+// simultaneous calls to the IMS API.
+func (i Config) InvalidateTokens(tokens []BatchToken, concurrency int) (BatchReport, error) {
+	if err := i.validateBatchConfig(concurrency); err != nil {
+		return BatchReport{}, fmt.Errorf("incomplete parameters for token invalidation: %w", err)
+	}
+	c, err := i.newIMSClient()
+	if err != nil {
+		return BatchReport{}, fmt.Errorf("error creating the IMS client: %w", err)
+	}
+
+	return runBatch(tokens, concurrency, func(t BatchToken) BatchResult {
+		config := i.withToken(t)
+		err := config.validateInvalidateTokenConfig()
+		if err == nil {
+			err = config.invalidateToken(c)
+		}
+		if err != nil {
+			return BatchResult{Status: "error", Reason: err.Error()}
+		}
+		return BatchResult{Status: "invalidated", Success: true}
+	}), nil
+}
+
+// runBatch processes the tokens with a pool of concurrency workers.
+func runBatch(tokens []BatchToken, concurrency int, process func(BatchToken) BatchResult) BatchReport {
+	results := make([]BatchResult, len(tokens))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(tokens)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range next {
+				r := process(tokens[n])
+				r.Line, r.Token, r.Type = tokens[n].Line, abbreviateToken(tokens[n].Token), tokens[n].Type
+				results[n] = r
+			}
+		}()
+	}
+	for n := range tokens {
+		next <- n
+	}
+	close(next)
+	wg.Wait()
+
+	report := BatchReport{Total: len(results), Results: results}
+	for _, r := range results {
+		if !r.Success {
+			report.Failed++
+		}
+	}
+	return report
+}
+
+// abbreviateToken keeps the ends of the token, enough to recognize it but not
+// to use it.
+func abbreviateToken(token string) string {
+	if len(token) < 20 {
+		return "***"
+	}
+	return token[:6] + "..." + token[len(token)-4:]
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adobe/imscli/mockims"
+)
+
+func TestParseBatchTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []BatchToken
+		wantErr string
+	}{
+		{
+			name:  "bare tokens",
+			input: "tok1\n\n  tok2  \n# comment\n",
+			want: []BatchToken{
+				{Line: 1, Token: "tok1", Type: BatchAccessToken},
+				{Line: 3, Token: "tok2", Type: BatchAccessToken},
+			},
+		},
+		{
+			name:  "JSON lines",
+			input: `{"token":"tok1","type":"refresh_token"}` + "\n" + `{"token":"tok2"}` + "\n" + `{"token":"tok3","type":"deviceToken"}`,
+			want: []BatchToken{
+				{Line: 1, Token: "tok1", Type: BatchRefreshToken},
+				{Line: 2, Token: "tok2", Type: BatchAccessToken},
+				{Line: 3, Token: "tok3", Type: BatchDeviceToken},
+			},
+		},
+		{name: "malformed JSON", input: "tok1\n{\"token\":", wantErr: "line 2: malformed JSON"},
+		{name: "missing token", input: `{"type":"accessToken"}`, wantErr: "line 1: missing token"},
+		{name: "unknown type", input: `{"token":"tok1","type":"idToken"}`, wantErr: `line 1: unknown token type "idToken"`},
+		{name: "empty", input: "\n# nothing\n", wantErr: "no token found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBatchTokens(strings.NewReader(tt.input), BatchAccessToken)
+			assertError(t, err, tt.wantErr)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("tokens = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateInvalidateTokens(t *testing.T) {
+	withTempConfigDir(t)
+	s, err := mockims.New(mockims.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+
+	config := Config{URL: srv.URL, ClientID: "cid", ClientSecret: "secret", Scopes: []string{"openid"},
+		Timeout: 5, NoCache: true}
+	var input strings.Builder
+	for range 5 {
+		token, err := config.AuthorizeClientCredentials()
+		if err != nil {
+			t.Fatalf("authorize: %v", err)
+		}
+		input.WriteString(token + "\n")
+	}
+	input.WriteString(`{"token":"not-a-token-issued-by-ims","type":"refreshToken"}` + "\n")
+	tokens, err := ReadBatchTokens("-", strings.NewReader(input.String()), BatchAccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := config.InvalidateTokens(tokens, 2)
+	if err != nil {
+		t.Fatalf("invalidate: %v", err)
+	}
+	if report.Total != 6 || report.Failed != 1 {
+		t.Fatalf("invalidate: total = %d, failed = %d, want 6 and 1", report.Total, report.Failed)
+	}
+	for n, r := range report.Results {
+		if r.Line != n+1 || strings.Contains(input.String(), r.Token) {
+			t.Errorf("result %d = %+v, want line %d and an abbreviated token", n, r, n+1)
+		}
+	}
+	if last := report.Results[5]; last.Status != "error" || last.Type != BatchRefreshToken {
+		t.Errorf("last result = %+v, want an error for the refresh token", last)
+	}
+
+	report, err = config.ValidateTokens(tokens[:5], 8)
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	for _, r := range report.Results {
+		if r.Success || r.Status != "invalid" || !strings.Contains(r.Reason, "token invalidated") {
+			t.Errorf("validate after invalidation: %+v, want an invalidated token", r)
+		}
+	}
+
+	if _, err := config.ValidateTokens(tokens, 0); err == nil || !strings.Contains(err.Error(), "invalid concurrency") {
+		t.Errorf("error = %v, want an invalid concurrency", err)
+	}
+}
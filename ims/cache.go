@@ -0,0 +1,361 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+// On-disk token cache. Tokens negotiated by the authorize flows are stored
+// under the user configuration directory (e.g. ~/.config/imscli/cache) and
+// reused while they are still valid, so repeated invocations with the same
+// client, scopes and organization do not hit IMS or relaunch the browser.
+
+package ims
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tokenCacheSkew is subtracted from the expiration of cached tokens, so a
+// token returned from the cache is still usable by whatever consumes it.
+const tokenCacheSkew = 2 * time.Minute
+
+// CacheEntry is a token stored in the on-disk token cache.
+type CacheEntry struct {
+	Key           string    `json:"key"`
+	Flow          string    `json:"flow"`
+	URL           string    `json:"url"`
+	ClientID      string    `json:"client_id"`
+	Organization  string    `json:"organization,omitempty"`
+	Scopes        []string  `json:"scopes,omitempty"`
+	Resource      []string  `json:"resource,omitempty"`
+	AccessToken   string    `json:"access_token"`
+	RefreshToken  string    `json:"refresh_token,omitempty"`
+	IDToken       string    `json:"id_token,omitempty"`
+	GrantedScopes []string  `json:"granted_scopes,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// Expired reports whether the cached token must not be reused anymore.
+func (e CacheEntry) Expired() bool {
+	return !time.Now().Add(tokenCacheSkew).Before(e.ExpiresAt)
+}
+
+// TokenCacheDir returns the directory holding the cached tokens.
+func TokenCacheDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find configuration directory: %w", err)
+	}
+	return filepath.Join(configDir, "imscli", "cache"), nil
+}
+
+// cacheKey identifies the token negotiated by the given flow. Scopes and
+// resources are sorted so that the order used on the command line does not
+// matter.
+func (i Config) cacheKey(flow string) string {
+	scopes := slices.Clone(i.Scopes)
+	slices.Sort(scopes)
+	resource := slices.Clone(i.Resource)
+	slices.Sort(resource)
+
+	h := sha256.New()
+	for _, part := range []string{
+		flow,
+		strings.TrimRight(i.URL, "/"),
+		i.ClientID,
+		i.Organization,
+		i.Account,
+		i.AuthorizationCode,
+		strings.Join(scopes, ","),
+		strings.Join(i.Metascopes, ","),
+		strings.Join(resource, ","),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cachedAuthorization is cachedAuthorizationInfo for the flows that only
+// return an access token.
+func (i Config) cachedAuthorization(flow string, authorize func() (string, time.Duration, error)) (string, error) {
+	info, err := i.cachedAuthorizationInfo(flow, func() (AuthorizationInfo, time.Duration, error) {
+		token, expiresIn, err := authorize()
+		return AuthorizationInfo{AccessToken: token}, expiresIn, err
+	})
+	return info.AccessToken, err
+}
+
+// cachedAuthorizationInfo returns a still valid token negotiated by the same
+// flow and parameters, or runs authorize and stores its result. The cache is
+// best effort: errors reading or writing it are logged and never fail the
+// command.
+func (i Config) cachedAuthorizationInfo(flow string, authorize func() (AuthorizationInfo, time.Duration, error)) (AuthorizationInfo, error) {
+	if i.NoCache {
+		info, expiresIn, err := authorize()
+		if err != nil {
+			return AuthorizationInfo{}, err
+		}
+		info.ExpiresAt = tokenExpiry(info.AccessToken, expiresIn, time.Now())
+		return info, nil
+	}
+
+	key := i.cacheKey(flow)
+	entry, err := loadCacheEntry(key)
+	switch {
+	case err == nil && !entry.Expired():
+		log.Printf("Using cached token, valid until %s.", entry.ExpiresAt.Format(time.RFC3339))
+		return AuthorizationInfo{
+			AccessToken:  entry.AccessToken,
+			RefreshToken: entry.RefreshToken,
+			IDToken:      entry.IDToken,
+			ExpiresAt:    entry.ExpiresAt,
+			Scopes:       entry.GrantedScopes,
+		}, nil
+	case err == nil:
+		log.Println("Cached token expired, negotiating a new one.")
+	case !errors.Is(err, os.ErrNotExist):
+		log.Printf("Ignoring unreadable token cache entry: %v", err)
+	}
+
+	info, expiresIn, err := authorize()
+	if err != nil {
+		return AuthorizationInfo{}, err
+	}
+
+	now := time.Now()
+	info.ExpiresAt = tokenExpiry(info.AccessToken, expiresIn, now)
+	if info.ExpiresAt.IsZero() {
+		log.Println("Unable to find the token expiration, the token will not be cached.")
+		return info, nil
+	}
+
+	err = storeCacheEntry(CacheEntry{
+		Key:           key,
+		Flow:          flow,
+		URL:           i.URL,
+		ClientID:      i.ClientID,
+		Organization:  i.Organization,
+		Scopes:        i.Scopes,
+		Resource:      i.Resource,
+		AccessToken:   info.AccessToken,
+		RefreshToken:  info.RefreshToken,
+		IDToken:       info.IDToken,
+		GrantedScopes: info.Scopes,
+		CreatedAt:     now,
+		ExpiresAt:     info.ExpiresAt,
+	})
+	if err != nil {
+		log.Printf("Unable to store the token in the cache: %v", err)
+	}
+	return info, nil
+}
+
+// tokenExpiry computes when a token expires, using the expires_in value of the
+// token response and the claims of the token itself. When both are available
+// the earliest wins. Returns the zero time if the expiration is unknown.
+func tokenExpiry(token string, expiresIn time.Duration, now time.Time) time.Time {
+	var expiry time.Time
+	if expiresIn > 0 {
+		expiry = now.Add(expiresIn)
+	}
+	if claimed := claimsExpiry(token); !claimed.IsZero() && (expiry.IsZero() || claimed.Before(expiry)) {
+		expiry = claimed
+	}
+	return expiry
+}
+
+// claimsExpiry reads the expiration from the payload of a JWT. Standard tokens
+// carry an exp claim in seconds; IMS access tokens carry created_at and
+// expires_in in milliseconds instead, sometimes encoded as strings.
+func claimsExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}
+	}
+
+	if exp, ok := numericClaim(claims["exp"]); ok {
+		return time.Unix(exp, 0)
+	}
+	createdAt, ok1 := numericClaim(claims["created_at"])
+	expiresIn, ok2 := numericClaim(claims["expires_in"])
+	if ok1 && ok2 {
+		return time.UnixMilli(createdAt + expiresIn)
+	}
+	return time.Time{}
+}
+
+func numericClaim(v any) (int64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return int64(v), true
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func loadCacheEntry(key string) (CacheEntry, error) {
+	dir, err := TokenCacheDir()
+	if err != nil {
+		return CacheEntry{}, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, key+".json"))
+	if err != nil {
+		return CacheEntry{}, err
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return CacheEntry{}, fmt.Errorf("error parsing cache entry %s: %w", key, err)
+	}
+	return entry, nil
+}
+
+// storeCacheEntry writes the entry readable only by the current user.
+func storeCacheEntry(entry CacheEntry) error {
+	dir, err := TokenCacheDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error marshalling cache entry: %w", err)
+	}
+	return writeFileAtomic(dir, entry.Key+".json", data)
+}
+
+// writeFileAtomic writes a file readable only by the current user. The data is
+// written to a temporary file first and renamed, so concurrent invocations
+// never read a partially written file.
+func writeFileAtomic(dir, name string, data []byte) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("error creating directory %s: %w", dir, err)
+	}
+	f, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", name, err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("error writing %s: %w", name, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error writing %s: %w", name, err)
+	}
+	if err := os.Rename(f.Name(), filepath.Join(dir, name)); err != nil {
+		return fmt.Errorf("error writing %s: %w", name, err)
+	}
+	return nil
+}
+
+// ListCachedTokens returns all the entries of the token cache, sorted by
+// expiration.
+func ListCachedTokens() ([]CacheEntry, error) {
+	dir, err := TokenCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("error listing the token cache: %w", err)
+	}
+
+	entries := make([]CacheEntry, 0, len(files))
+	for _, f := range files {
+		entry, err := loadCacheEntry(strings.TrimSuffix(filepath.Base(f), ".json"))
+		if err != nil {
+			log.Printf("Skipping unreadable cache entry %s: %v", f, err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b CacheEntry) int {
+		return a.ExpiresAt.Compare(b.ExpiresAt)
+	})
+	return entries, nil
+}
+
+// GetCachedToken returns the cache entry identified by the given key. Like git
+// object names, any unambiguous prefix of the key is accepted.
+func GetCachedToken(prefix string) (CacheEntry, error) {
+	if prefix == "" {
+		return CacheEntry{}, fmt.Errorf("missing cache key parameter")
+	}
+	entries, err := ListCachedTokens()
+	if err != nil {
+		return CacheEntry{}, err
+	}
+
+	var found []CacheEntry
+	for _, e := range entries {
+		if strings.HasPrefix(e.Key, prefix) {
+			found = append(found, e)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return CacheEntry{}, fmt.Errorf("no cached token found for key %s", prefix)
+	case 1:
+		return found[0], nil
+	default:
+		return CacheEntry{}, fmt.Errorf("ambiguous cache key %s matches %d tokens", prefix, len(found))
+	}
+}
+
+// PurgeTokenCache deletes the cached tokens, or only the expired ones when
+// expiredOnly is set. Unreadable entries are always deleted. Returns the number
+// of deleted entries.
+func PurgeTokenCache(expiredOnly bool) (int, error) {
+	dir, err := TokenCacheDir()
+	if err != nil {
+		return 0, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return 0, fmt.Errorf("error listing the token cache: %w", err)
+	}
+
+	deleted := 0
+	for _, f := range files {
+		if expiredOnly {
+			entry, err := loadCacheEntry(strings.TrimSuffix(filepath.Base(f), ".json"))
+			if err == nil && !entry.Expired() {
+				continue
+			}
+		}
+		if err := os.Remove(f); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return deleted, fmt.Errorf("error deleting cache entry %s: %w", f, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
@@ -0,0 +1,273 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// withTempConfigDir points os.UserConfigDir at a temporary directory so the
+// tests never touch the real token cache.
+func withTempConfigDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("AppData", dir)
+	return dir
+}
+
+// fakeJWT builds an unsigned token with the given payload.
+func fakeJWT(payload string) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." + enc.EncodeToString([]byte(payload)) + ".sig"
+}
+
+func TestCacheKey(t *testing.T) {
+	base := Config{URL: "https://ims.example.com", ClientID: "c", Organization: "o", Scopes: []string{"openid", "AdobeID"}}
+
+	if base.cacheKey("user") != withField(base, func(c *Config) { c.Scopes = []string{"AdobeID", "openid"} }).cacheKey("user") {
+		t.Error("scope order must not change the cache key")
+	}
+	if base.cacheKey("user") != withField(base, func(c *Config) { c.URL = "https://ims.example.com/" }).cacheKey("user") {
+		t.Error("trailing slash in the URL must not change the cache key")
+	}
+
+	different := map[string]Config{
+		"client ID":    withField(base, func(c *Config) { c.ClientID = "other" }),
+		"organization": withField(base, func(c *Config) { c.Organization = "other" }),
+		"scopes":       withField(base, func(c *Config) { c.Scopes = []string{"openid"} }),
+		"resource":     withField(base, func(c *Config) { c.Resource = []string{"https://api.example.com"} }),
+		"URL":          withField(base, func(c *Config) { c.URL = "https://ims-stg.example.com" }),
+	}
+	for name, c := range different {
+		if c.cacheKey("user") == base.cacheKey("user") {
+			t.Errorf("different %s must change the cache key", name)
+		}
+	}
+	if base.cacheKey("user") == base.cacheKey("pkce") {
+		t.Error("different flows must not share the cache key")
+	}
+}
+
+func TestTokenExpiry(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name      string
+		token     string
+		expiresIn time.Duration
+		want      time.Time
+	}{
+		{name: "opaque token with expires_in", token: "opaque", expiresIn: time.Hour, want: now.Add(time.Hour)},
+		{name: "opaque token without expires_in", token: "opaque", want: time.Time{}},
+		{name: "exp claim", token: fakeJWT(`{"exp":1700000600}`), want: time.Unix(1700000600, 0)},
+		{
+			name:  "IMS created_at and expires_in claims",
+			token: fakeJWT(`{"created_at":"1700000000000","expires_in":"86400000"}`),
+			want:  time.UnixMilli(1700000000000 + 86400000),
+		},
+		{
+			name:      "earliest of claims and expires_in",
+			token:     fakeJWT(`{"exp":1700000600}`),
+			expiresIn: time.Hour,
+			want:      time.Unix(1700000600, 0),
+		},
+		{
+			name:      "expires_in earlier than claims",
+			token:     fakeJWT(`{"exp":1700099999}`),
+			expiresIn: time.Minute,
+			want:      now.Add(time.Minute),
+		},
+		{name: "malformed payload", token: "a.!!!.c", expiresIn: time.Minute, want: now.Add(time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tokenExpiry(tt.token, tt.expiresIn, now)
+			if !got.Equal(tt.want) {
+				t.Errorf("tokenExpiry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCachedAuthorization(t *testing.T) {
+	withTempConfigDir(t)
+	c := Config{URL: "https://ims.example.com", ClientID: "c", Scopes: []string{"openid"}}
+
+	calls := 0
+	authorize := func() (string, time.Duration, error) {
+		calls++
+		return fmt.Sprintf("token-%d", calls), time.Hour, nil
+	}
+
+	for range 2 {
+		token, err := c.cachedAuthorization("clientCredentials", authorize)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if token != "token-1" {
+			t.Errorf("token = %q, want %q", token, "token-1")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("authorize called %d times, want 1", calls)
+	}
+
+	c.NoCache = true
+	token, err := c.cachedAuthorization("clientCredentials", authorize)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "token-2" {
+		t.Errorf("with NoCache token = %q, want %q", token, "token-2")
+	}
+}
+
+func TestCachedAuthorization_Expired(t *testing.T) {
+	withTempConfigDir(t)
+	c := Config{URL: "https://ims.example.com", ClientID: "c", Scopes: []string{"openid"}}
+
+	// Shorter than tokenCacheSkew, so the stored token is never reused.
+	calls := 0
+	authorize := func() (string, time.Duration, error) {
+		calls++
+		return "token", time.Minute, nil
+	}
+	for range 2 {
+		if _, err := c.cachedAuthorization("clientCredentials", authorize); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("authorize called %d times, want 2", calls)
+	}
+}
+
+func TestCachedAuthorization_NotCachedWithoutExpiry(t *testing.T) {
+	withTempConfigDir(t)
+	c := Config{URL: "https://ims.example.com", ClientID: "c"}
+
+	_, err := c.cachedAuthorization("service", func() (string, time.Duration, error) {
+		return "opaque", 0, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries, err := ListCachedTokens()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("got %d cache entries, want 0", len(entries))
+	}
+}
+
+func TestCacheEntryPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX permissions are not supported on Windows")
+	}
+	withTempConfigDir(t)
+	c := Config{URL: "https://ims.example.com", ClientID: "c"}
+	_, err := c.cachedAuthorization("service", func() (string, time.Duration, error) {
+		return "token", time.Hour, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dir, err := TokenCacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(dir, c.cacheKey("service")+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("cache entry permissions = %o, want 600", perm)
+	}
+}
+
+func TestGetCachedTokenAndPurge(t *testing.T) {
+	withTempConfigDir(t)
+	now := time.Now()
+	for _, e := range []CacheEntry{
+		{Key: "aaa111", AccessToken: "valid", ExpiresAt: now.Add(time.Hour)},
+		{Key: "aaa222", AccessToken: "expired", ExpiresAt: now.Add(-time.Hour)},
+		{Key: "bbb333", AccessToken: "other", ExpiresAt: now.Add(time.Hour)},
+	} {
+		if err := storeCacheEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	e, err := GetCachedToken("bbb")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.AccessToken != "other" {
+		t.Errorf("AccessToken = %q, want %q", e.AccessToken, "other")
+	}
+	_, err = GetCachedToken("aaa")
+	assertError(t, err, "ambiguous cache key")
+	_, err = GetCachedToken("ccc")
+	assertError(t, err, "no cached token found")
+
+	n, err := PurgeTokenCache(true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("purged %d expired entries, want 1", n)
+	}
+	n, err = PurgeTokenCache(false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("purged %d entries, want 2", n)
+	}
+}
+
+func TestCachedAuthorizationInfo_FullResponse(t *testing.T) {
+	withTempConfigDir(t)
+	c := Config{URL: "https://ims.example.com", ClientID: "c", Scopes: []string{"openid"}}
+
+	calls := 0
+	authorize := func() (AuthorizationInfo, time.Duration, error) {
+		calls++
+		return AuthorizationInfo{AccessToken: "at", RefreshToken: "rt", IDToken: "idt", Scopes: []string{"openid"}}, time.Hour, nil
+	}
+
+	first, err := c.cachedAuthorizationInfo("pkce", authorize)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cached, err := c.cachedAuthorizationInfo("pkce", authorize)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("authorize called %d times, want 1", calls)
+	}
+	if cached.RefreshToken != "rt" || cached.IDToken != "idt" || len(cached.Scopes) != 1 {
+		t.Errorf("cached response lost fields: %+v", cached)
+	}
+	if !cached.ExpiresAt.Equal(first.ExpiresAt) {
+		t.Errorf("ExpiresAt = %v, want %v", cached.ExpiresAt, first.ExpiresAt)
+	}
+}
@@ -12,33 +12,353 @@ package ims
 
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 )
 
+// retryBaseDelay is the delay before the first retry, doubled at each attempt.
+var retryBaseDelay = 500 * time.Millisecond
+
 // httpClient creates an HTTP client based on the received configuration.
 func (i Config) httpClient() (*http.Client, error) {
+	tlsConfig, err := i.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	proxy, err := i.proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = tlsConfig
+	t.Proxy = proxy
+
+	var transport http.RoundTripper = t
+	if i.ProxyIgnoreTLS {
+		transport = i.ignoreProxyTLS(t)
+	}
+	// Trace each attempt of the retried requests.
+	if i.Trace || i.TraceHAR != "" {
+		transport = i.traceTransport(transport)
+	}
+	if i.Retries > 0 {
+		transport = &retryTransport{next: transport, retries: i.Retries, maxWait: i.RetryMaxWait, verbose: i.Verbose}
+	}
+
+	client := &http.Client{
+		Timeout:   time.Duration(i.Timeout) * time.Second,
+		Transport: transport,
+	}
+	return client, nil
+}
 
+// proxyFunc selects the proxy of each request: the proxy of ProxyURL, or the
+// proxy of the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables,
+// unless the host is in NoProxy. ProxyUser and ProxyPassword are the
+// credentials of proxies without credentials in their URL.
+func (i Config) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	proxy := http.ProxyFromEnvironment
 	if i.ProxyURL != "" {
 		p, err := url.Parse(i.ProxyURL)
 		if err != nil {
 			return nil, fmt.Errorf("proxy URL is malformed: %w", err)
 		}
-		t := http.DefaultTransport.(*http.Transport).Clone()
-		t.Proxy = http.ProxyURL(p)
-		if i.ProxyIgnoreTLS {
-			t.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		switch p.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("unsupported proxy URL scheme %q, use http, https, socks5 or socks5h", p.Scheme)
 		}
-		client := &http.Client{
-			Timeout:   time.Duration(i.Timeout) * time.Second,
-			Transport: t,
+		if p.Host == "" {
+			return nil, fmt.Errorf("proxy URL is malformed: missing host")
 		}
-		return client, nil
+		proxy = http.ProxyURL(p)
 	}
-	client := &http.Client{
-		Timeout: time.Duration(i.Timeout) * time.Second,
+
+	noProxy := i.NoProxy
+	user := i.ProxyUser
+	password := i.ProxyPassword
+	return func(req *http.Request) (*url.URL, error) {
+		if bypassProxy(req.URL, noProxy) {
+			return nil, nil
+		}
+		u, err := proxy(req)
+		if u != nil && u.User == nil && user != "" {
+			c := *u
+			c.User = url.UserPassword(user, password)
+			u = &c
+		}
+		return u, err
+	}, nil
+}
+
+// ignoreProxyTLS disables the certificate verification of the connections
+// made through an HTTP(S) proxy, which may be a TLS inspecting proxy. The
+// connections made directly or through a SOCKS5 proxy, where TLS is end to
+// end, are still verified.
+func (i Config) ignoreProxyTLS(t *http.Transport) http.RoundTripper {
+	insecure := t.Clone()
+	insecure.TLSClientConfig.InsecureSkipVerify = true
+	if i.ProxyURL != "" && len(i.NoProxy) == 0 && strings.HasPrefix(i.ProxyURL, "http") {
+		// Every request goes through the proxy.
+		return insecure
+	}
+	return &proxyTLSTransport{secure: t, insecure: insecure}
+}
+
+// proxyTLSTransport sends the requests going through an HTTP(S) proxy with the
+// insecure transport.
+type proxyTLSTransport struct {
+	secure, insecure *http.Transport
+}
+
+func (t *proxyTLSTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	proxy, err := t.secure.Proxy(req)
+	if err == nil && proxy != nil && (proxy.Scheme == "http" || proxy.Scheme == "https") {
+		return t.insecure.RoundTrip(req)
+	}
+	return t.secure.RoundTrip(req)
+}
+
+// bypassProxy reports whether the host of the URL matches the NoProxy list,
+// with the conventions of NO_PROXY: "*" matches every host, IP addresses and
+// CIDR ranges match the IP addresses, and domains match the domain and its
+// subdomains, with or without a leading dot. An entry with a port only
+// matches that port.
+func bypassProxy(u *url.URL, noProxy []string) bool {
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[u.Scheme]
+	}
+	ip := net.ParseIP(host)
+
+	for _, entry := range noProxy {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		entryHost, entryPort := entry, ""
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			entryHost, entryPort = h, p
+		}
+		if entryPort != "" && entryPort != port {
+			continue
+		}
+		if entryIP := net.ParseIP(entryHost); entryIP != nil {
+			if ip != nil && entryIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		entryHost = strings.TrimPrefix(strings.TrimPrefix(entryHost, "*"), ".")
+		if host == entryHost || strings.HasSuffix(host, "."+entryHost) {
+			return true
+		}
+	}
+	return false
+}
+
+// tlsConfig builds the TLS configuration of the connections to IMS and to the
+// proxy: the system CAs plus the CAs of CAFile, and the client certificate
+// presented to the gateways requiring mutual TLS.
+func (i Config) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if i.CAFile != "" {
+		data, err := os.ReadFile(i.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA file %s: %w", i.CAFile, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			// The system pool is not available on every platform, e.g. old Windows versions.
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no PEM encoded certificate found in CA file %s", i.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	switch {
+	case i.ClientCert != "":
+		// The key may be in the same file as the certificate.
+		keyFile := i.ClientKey
+		if keyFile == "" {
+			keyFile = i.ClientCert
+		}
+		cert, err := tls.LoadX509KeyPair(i.ClientCert, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate %s: %w", i.ClientCert, err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	case i.ClientKey != "":
+		return nil, fmt.Errorf("missing client certificate parameter for the client key")
+	}
+
+	return config, nil
+}
+
+// retryTransport retries the requests failing with a transient error, with an
+// exponential backoff and jitter. Only the requests that can safely be sent
+// twice are retried, see retryable.
+type retryTransport struct {
+	next    http.RoundTripper
+	retries int
+	// maxWait bounds the delay between two attempts. The request is not
+	// retried when the server asks to wait longer with Retry-After.
+	maxWait time.Duration
+	verbose bool
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !retryable(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		res, err := t.next.RoundTrip(req)
+		if attempt >= t.retries || !transient(res, err) {
+			return res, err
+		}
+		wait, ok := t.delay(attempt, res)
+		if !ok {
+			return res, err
+		}
+
+		var reason string
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = res.Status
+			_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+			_ = res.Body.Close()
+		}
+		if t.verbose {
+			log.Printf("%s %s failed (%s), retrying in %s (%d/%d).", req.Method, req.URL.Redacted(), reason,
+				wait.Round(time.Millisecond), attempt+1, t.retries)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// delay returns the time to wait before the next attempt: the Retry-After
+// delay requested by the server, or an exponential backoff with jitter. It
+// reports false when the server asks to wait longer than the maximum.
+func (t *retryTransport) delay(attempt int, res *http.Response) (time.Duration, bool) {
+	if res != nil {
+		if wait, ok := retryAfter(res.Header.Get("Retry-After")); ok {
+			return wait, wait <= t.maxWait
+		}
+	}
+	backoff := retryBaseDelay << attempt
+	if backoff <= 0 || backoff > t.maxWait {
+		backoff = t.maxWait
+	}
+	// Full jitter in the upper half, so concurrent jobs spread their retries.
+	return backoff/2 + rand.N(backoff/2+1), true
+}
+
+// retryAfter parses a Retry-After header, in seconds or as an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// transient reports whether a failed attempt is worth retrying: rate limiting,
+// unavailable gateways and dropped connections.
+func transient(res *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) ||
+			errors.Is(err, io.ErrUnexpectedEOF) || (errors.As(err, &netErr) && netErr.Timeout())
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryable reports whether a request can be sent again without side effects:
+// the idempotent methods, like the profile and organizations calls, and the
+// POST calls that only read, like the token validation and the client
+// credentials grant. Authorization codes and refresh tokens can only be used
+// once, and the exchange and registration calls create resources.
+func retryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+	default:
+		return false
+	}
+	if req.GetBody == nil {
+		return false
+	}
+
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/ims/validate_token/v1"):
+		return true
+	case strings.Contains(req.URL.Path, "/ims/token/"):
+		body, err := req.GetBody()
+		if err != nil {
+			return false
+		}
+		defer func() { _ = body.Close() }()
+		data, err := io.ReadAll(io.LimitReader(body, 1<<16))
+		if err != nil {
+			return false
+		}
+		form, err := url.ParseQuery(string(data))
+		return err == nil && form.Get("grant_type") == "client_credentials"
+	default:
+		return false
 	}
-	return client, nil
 }
@@ -0,0 +1,263 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adobe/imscli/keys"
+)
+
+func TestRetryTransport(t *testing.T) {
+	orig := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	t.Cleanup(func() { retryBaseDelay = orig })
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		form       url.Values
+		status     int
+		retryAfter string
+		wantCalls  int32
+		wantStatus int
+	}{
+		{name: "get", method: http.MethodGet, path: "/ims/profile/v1", status: http.StatusServiceUnavailable,
+			wantCalls: 3, wantStatus: http.StatusOK},
+		{name: "validate", method: http.MethodPost, path: "/ims/validate_token/v1", form: url.Values{"token": {"t"}},
+			status: http.StatusTooManyRequests, retryAfter: "0", wantCalls: 3, wantStatus: http.StatusOK},
+		{name: "client credentials", method: http.MethodPost, path: "/ims/token/v3",
+			form: url.Values{"grant_type": {"client_credentials"}}, status: http.StatusBadGateway,
+			wantCalls: 3, wantStatus: http.StatusOK},
+		{name: "authorization code", method: http.MethodPost, path: "/ims/token/v3",
+			form: url.Values{"grant_type": {"authorization_code"}}, status: http.StatusServiceUnavailable,
+			wantCalls: 1, wantStatus: http.StatusServiceUnavailable},
+		{name: "invalidate", method: http.MethodPost, path: "/ims/invalidate_token/v2", form: url.Values{"token": {"t"}},
+			status: http.StatusServiceUnavailable, wantCalls: 1, wantStatus: http.StatusServiceUnavailable},
+		{name: "not transient", method: http.MethodGet, path: "/ims/profile/v1", status: http.StatusBadRequest,
+			wantCalls: 1, wantStatus: http.StatusBadRequest},
+		{name: "retry after too long", method: http.MethodGet, path: "/ims/profile/v1",
+			status: http.StatusTooManyRequests, retryAfter: "60", wantCalls: 1, wantStatus: http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Fail twice, then succeed if the body is sent again.
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil || r.PostForm.Encode() != tt.form.Encode() {
+					t.Errorf("form = %v, want %v", r.PostForm, tt.form)
+				}
+				if calls.Add(1) <= 2 {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(tt.status)
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			config := Config{Timeout: 5, Retries: 3, RetryMaxWait: time.Second}
+			client, err := config.httpClient()
+			if err != nil {
+				t.Fatal(err)
+			}
+			req, err := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(tt.form.Encode()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.form != nil {
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+			res, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_ = res.Body.Close()
+			if res.StatusCode != tt.wantStatus || calls.Load() != tt.wantCalls {
+				t.Errorf("status = %d after %d calls, want %d after %d calls",
+					res.StatusCode, calls.Load(), tt.wantStatus, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if got, ok := retryAfter("7"); !ok || got != 7*time.Second {
+		t.Errorf("retryAfter(7) = %v, %v", got, ok)
+	}
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if got, ok := retryAfter(date); !ok || got < 59*time.Minute {
+		t.Errorf("retryAfter(%s) = %v, %v", date, got, ok)
+	}
+	if _, ok := retryAfter("soon"); ok {
+		t.Error("retryAfter(soon) should be invalid")
+	}
+}
+
+func TestHTTPClient_TLS(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name string, data []byte) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	clientKey, clientCert, err := keys.Generate(keys.GenerateOptions{Bits: 2048, Format: keys.FormatPKCS8,
+		Subject: pkix.Name{CommonName: "client"}, Validity: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(clientCert)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	caFile := writeFile("ca.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+	certFile := writeFile("client.crt", clientCert)
+	keyFile := writeFile("client.key", clientKey)
+	bundleFile := writeFile("client.pem", append(append([]byte{}, clientCert...), clientKey...))
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{name: "unknown CA", config: Config{}, wantErr: "certificate"},
+		{name: "missing client certificate", config: Config{CAFile: caFile}, wantErr: "certificate"},
+		{name: "client certificate", config: Config{CAFile: caFile, ClientCert: certFile, ClientKey: keyFile}},
+		{name: "client certificate and key in one file", config: Config{CAFile: caFile, ClientCert: bundleFile}},
+		{name: "key without certificate", config: Config{CAFile: caFile, ClientKey: keyFile},
+			wantErr: "missing client certificate"},
+		{name: "invalid CA file", config: Config{CAFile: keyFile}, wantErr: "no PEM encoded certificate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Timeout = 5
+			client, err := tt.config.httpClient()
+			if err == nil {
+				var res *http.Response
+				if res, err = client.Get(srv.URL); err == nil {
+					_ = res.Body.Close()
+				}
+			}
+			assertError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestBypassProxy(t *testing.T) {
+	noProxy := []string{"internal.example.com", ".corp.example", "10.0.0.0/8", "192.168.1.1", "localhost:8080"}
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://internal.example.com/ims", true},
+		{"https://api.internal.example.com/ims", true},
+		{"https://notinternal.example.com/ims", false},
+		{"https://ims.corp.example/ims", true},
+		{"https://corp.example/ims", true},
+		{"http://10.1.2.3/ims", true},
+		{"http://11.1.2.3/ims", false},
+		{"http://192.168.1.1:8080/ims", true},
+		{"http://localhost:8080/ims", true},
+		{"http://localhost:8081/ims", false},
+		{"https://ims-na1.adobelogin.com/ims", false},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := bypassProxy(u, noProxy); got != tt.want {
+			t.Errorf("bypassProxy(%s) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+	if u, _ := url.Parse("https://any.example.com"); !bypassProxy(u, []string{"*"}) {
+		t.Error("* must match every host")
+	}
+}
+
+func TestHTTPClient_Proxy(t *testing.T) {
+	// A forward proxy answering the requests itself.
+	var proxied atomic.Int32
+	var proxyAuth atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Add(1)
+		proxyAuth.Store(r.Header.Get("Proxy-Authorization"))
+	}))
+	t.Cleanup(proxy.Close)
+	direct := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	t.Cleanup(direct.Close)
+	directURL, err := url.Parse(direct.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		config      Config
+		wantProxied bool
+		wantAuth    string
+		wantErr     string
+	}{
+		{name: "proxy", config: Config{ProxyURL: proxy.URL}, wantProxied: true},
+		{name: "credentials in URL", config: Config{ProxyURL: strings.Replace(proxy.URL, "://", "://u:p@", 1)},
+			wantProxied: true, wantAuth: "Basic dTpw"},
+		{name: "credentials flags", config: Config{ProxyURL: proxy.URL, ProxyUser: "u", ProxyPassword: "p"},
+			wantProxied: true, wantAuth: "Basic dTpw"},
+		{name: "no proxy", config: Config{ProxyURL: proxy.URL, NoProxy: []string{directURL.Hostname()}}},
+		{name: "no proxy and ignore TLS", config: Config{ProxyURL: proxy.URL, ProxyIgnoreTLS: true,
+			NoProxy: []string{directURL.Host}}},
+		{name: "unsupported scheme", config: Config{ProxyURL: "ftp://proxy.example.com"}, wantErr: "unsupported proxy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxied.Store(0)
+			proxyAuth.Store("")
+			tt.config.Timeout = 5
+			client, err := tt.config.httpClient()
+			assertError(t, err, tt.wantErr)
+			if err != nil {
+				return
+			}
+			res, err := client.Get(direct.URL)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_ = res.Body.Close()
+			if got := proxied.Load() == 1; got != tt.wantProxied {
+				t.Errorf("proxied = %v, want %v", got, tt.wantProxied)
+			}
+			if got := proxyAuth.Load(); got != tt.wantAuth {
+				t.Errorf("Proxy-Authorization = %q, want %q", got, tt.wantAuth)
+			}
+		})
+	}
+}
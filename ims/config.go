@@ -17,6 +17,7 @@ package ims
 import (
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/adobe/ims-go/ims"
 )
@@ -24,38 +25,71 @@ import (
 // Config holds all parameters needed to interact with the IMS API.
 // Fields are populated from CLI flags, environment variables, or a config file.
 type Config struct {
-	URL                   string
-	ClientID              string
-	ClientSecret          string
-	PrivateKeyPath        string
-	Organization          string
-	Account               string
-	Scopes                []string
-	Metascopes            []string
-	AccessToken           string
-	RefreshToken          string
-	DeviceToken           string
-	ServiceToken          string
-	AuthorizationCode     string
-	ProfileAPIVersion     string
-	OrgsAPIVersion        string
-	Timeout               int
-	ProxyURL              string
-	ProxyIgnoreTLS        bool
-	PublicClient          bool
-	UserID                string
-	Cascading             bool
-	Token                 string
-	Port                  int
-	FullOutput            bool
-	Verbose               bool
-	Guid                  string
-	AuthSrc               string
-	DecodeFulfillableData bool
-	ClientName            string
-	RedirectURIs          []string
-	RedirectURI           string
-	Resource              []string
+	URL                     string
+	ClientID                string
+	ClientSecret            string
+	PrivateKeyPath          string
+	PrivateKeyPassphrase    string
+	Organization            string
+	Account                 string
+	Scopes                  []string
+	Metascopes              []string
+	AccessToken             string
+	RefreshToken            string
+	DeviceToken             string
+	ServiceToken            string
+	AuthorizationCode       string
+	ProfileAPIVersion       string
+	OrgsAPIVersion          string
+	Timeout                 int
+	Retries                 int
+	RetryMaxWait            time.Duration
+	Trace                   bool
+	TraceHAR                string
+	ProxyURL                string
+	ProxyIgnoreTLS          bool
+	ProxyUser               string
+	ProxyPassword           string
+	NoProxy                 []string
+	CAFile                  string
+	ClientCert              string
+	ClientKey               string
+	PublicClient            bool
+	UserID                  string
+	Cascading               bool
+	Token                   string
+	Port                    int
+	FullOutput              bool
+	Verbose                 bool
+	Guid                    string
+	AuthSrc                 string
+	DecodeFulfillableData   bool
+	FulfillableDataDecoders map[string]string
+	ClientName              string
+	RedirectURIs            []string
+	GrantTypes              []string
+	ResponseTypes           []string
+	TokenEndpointAuthMethod string
+	ClientURI               string
+	LogoURI                 string
+	TosURI                  string
+	PolicyURI               string
+	Contacts                []string
+	JWKSURI                 string
+	SoftwareID              string
+	SoftwareVersion         string
+	SoftwareStatement       string
+	Metadata                string
+	RedirectURI             string
+	RegistrationAccessToken string
+	RegistrationClientURI   string
+	Resource                []string
+	NoCache                 bool
+	Output                  string
+	JWKS                    string
+	NoBrowser               bool
+	SecretStore             string
+	SecretPassphrase        string
 }
 
 // TokenInfo holds the response data from token-related IMS API calls.
@@ -65,10 +99,23 @@ type TokenInfo struct {
 	Info        string
 }
 
-// RefreshInfo extends TokenInfo with the new refresh token returned by a token refresh.
+// RefreshInfo extends TokenInfo with the new refresh token and the lifetime of
+// the access token returned by a token refresh.
 type RefreshInfo struct {
 	TokenInfo
 	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+// AuthorizationInfo holds the full token response of a user authorization:
+// besides the access token, the refresh and ID tokens needed to keep the
+// session alive without logging in again.
+type AuthorizationInfo struct {
+	AccessToken  string
+	RefreshToken string
+	IDToken      string
+	ExpiresAt    time.Time
+	Scopes       []string
 }
 
 func (i Config) resolveToken() (string, ims.TokenType, error) {
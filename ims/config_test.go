@@ -512,6 +512,33 @@ func TestNewIMSClient(t *testing.T) {
 	}
 }
 
+func TestParseProfile(t *testing.T) {
+	body := `{"userId":"u1","email":"u1@example.com","account_type":"type1","roles":[{"named_role":"org_admin",` +
+		`"organization":"O1"}],"projectedProductContext":[{"prodCtx":{"serviceCode":"sc"}}],"extra":true}`
+	p, err := parseProfile([]byte(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.UserID != "u1" || p.Email != "u1@example.com" || p.AccountType != "type1" || p.Body != body {
+		t.Errorf("got %+v", p)
+	}
+	if len(p.Roles) != 1 || p.Roles[0].NamedRole != "org_admin" || p.Roles[0].Organization != "O1" {
+		t.Errorf("roles = %+v", p.Roles)
+	}
+	if len(p.ProjectedProductContext) != 1 || p.ProjectedProductContext[0].ProdCtx.ServiceCode != "sc" {
+		t.Errorf("projectedProductContext = %+v", p.ProjectedProductContext)
+	}
+
+	// A field of an unexpected type keeps the rest of the profile.
+	p, err = parseProfile([]byte(`{"userId":"u1","roles":"none"}`))
+	if err != nil || p.UserID != "u1" {
+		t.Errorf("got %+v, %v, want the user ID", p, err)
+	}
+
+	_, err = parseProfile([]byte("not json"))
+	assertError(t, err, "invalid JSON")
+}
+
 func TestDecodeProfile(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -524,14 +551,14 @@ func TestDecodeProfile(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := decodeProfile([]byte(tt.input))
+			_, err := decodeProfile([]byte(tt.input), builtinFulfillableServiceCodes)
 			assertError(t, err, tt.wantErr)
 		})
 	}
 }
 
-func TestFindFulfillableData(t *testing.T) {
-	// Verify that findFulfillableData doesn't panic on various data structures.
+func TestDecodeFulfillableData_Panics(t *testing.T) {
+	// Verify that decodeFulfillableData doesn't panic on various data structures.
 	tests := []struct {
 		name  string
 		input interface{}
@@ -549,7 +576,7 @@ func TestFindFulfillableData(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Should not panic.
-			findFulfillableData(tt.input)
+			decodeFulfillableData(tt.input, builtinFulfillableServiceCodes)
 		})
 	}
 }
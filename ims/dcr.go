@@ -8,49 +8,282 @@
 // OF ANY KIND, either express or implied. See the License for the specific language
 // governing permissions and limitations under the License.
 
-// Dynamic Client Registration (DCR): POST JSON to IMS /ims/register.
+// Dynamic Client Registration (DCR): POST JSON to IMS /ims/register, and the
+// client configuration endpoint of RFC 7592.
 
 package ims
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/adobe/ims-go/ims"
 )
 
+// DCRClient is a client registration, as returned by the registration (RFC
+// 7591) and client configuration (RFC 7592) endpoints.
+type DCRClient struct {
+	ClientID              string `json:"client_id"`
+	ClientSecret          string `json:"client_secret,omitempty"`
+	ClientIDIssuedAt      int64  `json:"client_id_issued_at,omitempty"`
+	ClientSecretExpiresAt int64  `json:"client_secret_expires_at,omitempty"`
+	DCRMetadata
+	RegistrationAccessToken string `json:"registration_access_token,omitempty"`
+	RegistrationClientURI   string `json:"registration_client_uri,omitempty"`
+	// Body is the response of IMS, including the metadata not parsed above.
+	Body string `json:"-"`
+}
+
+// dcrReadOnlyFields are the fields of a registration set by the server, not
+// sent back in an update (RFC 7592, section 2.2).
+var dcrReadOnlyFields = []string{
+	"registration_access_token", "registration_client_uri", "client_id_issued_at", "client_secret_expires_at",
+}
+
 func (i Config) validateDCRConfig() error {
 	switch {
 	case i.URL == "":
 		return fmt.Errorf("missing IMS base URL parameter")
 	case !validateURL(i.URL):
 		return fmt.Errorf("invalid IMS base URL parameter")
-	case i.ClientName == "":
-		return fmt.Errorf("missing client name parameter")
-	case len(i.RedirectURIs) == 0:
-		return fmt.Errorf("missing redirect URIs parameter")
+	}
+	m, err := i.dcrMetadata()
+	if err != nil {
+		return err
+	}
+	return m.validate()
+}
+
+// validateDCRClientConfig checks the parameters of the client configuration
+// endpoint.
+func (i Config) validateDCRClientConfig() error {
+	switch {
+	case i.RegistrationAccessToken == "":
+		return fmt.Errorf("missing registration access token parameter")
+	case i.RegistrationClientURI != "" && !validateURL(i.RegistrationClientURI):
+		return fmt.Errorf("invalid registration client URI parameter")
+	case i.RegistrationClientURI != "":
+		return nil
+	case i.ClientID == "":
+		return fmt.Errorf("missing clientID or registration client URI parameter")
+	case i.URL == "":
+		return fmt.Errorf("missing IMS base URL parameter")
+	case !validateURL(i.URL):
+		return fmt.Errorf("invalid IMS base URL parameter")
 	default:
 		return nil
 	}
 }
 
-func (i Config) DCRRegister() (string, error) {
+// DCRRegister registers a new client with the metadata of the configuration.
+// The registration goes through the IMS client, unless the metadata go beyond
+// the client name, redirect URIs and scope it supports.
+func (i Config) DCRRegister() (DCRClient, error) {
 	if err := i.validateDCRConfig(); err != nil {
-		return "", fmt.Errorf("invalid parameters for client registration: %w", err)
+		return DCRClient{}, fmt.Errorf("invalid parameters for client registration: %w", err)
+	}
+	metadata, err := i.dcrMetadata()
+	if err != nil {
+		return DCRClient{}, fmt.Errorf("invalid parameters for client registration: %w", err)
+	}
+	if metadata.extended() {
+		return i.dcrRegisterMetadata(metadata)
 	}
 
 	c, err := i.newIMSClient()
 	if err != nil {
-		return "", fmt.Errorf("error creating the IMS client: %w", err)
+		return DCRClient{}, fmt.Errorf("error creating the IMS client: %w", err)
 	}
 
 	resp, err := c.DCR(&ims.DCRRequest{
-		ClientName:   i.ClientName,
-		RedirectURIs: i.RedirectURIs,
-		Scopes:       i.Scopes,
+		ClientName:   metadata.ClientName,
+		RedirectURIs: metadata.RedirectURIs,
+		Scopes:       strings.Fields(metadata.Scope),
 	})
 	if err != nil {
-		return "", fmt.Errorf("error during client registration: %w", err)
+		return DCRClient{}, fmt.Errorf("error during client registration: %w", err)
+	}
+
+	return i.parseDCRClient(resp.Body)
+}
+
+// dcrRegisterMetadata registers a new client with metadata the IMS client
+// cannot send.
+func (i Config) dcrRegisterMetadata(metadata DCRMetadata) (DCRClient, error) {
+	payload, err := json.Marshal(metadata)
+	if err != nil {
+		return DCRClient{}, fmt.Errorf("error building the registration payload: %w", err)
+	}
+	body, status, err := i.dcrRequest(http.MethodPost, strings.TrimRight(i.URL, "/")+"/ims/register", "", payload)
+	if err != nil {
+		return DCRClient{}, fmt.Errorf("error during client registration: %w", err)
+	}
+	if status < 200 || status >= 300 {
+		return DCRClient{}, fmt.Errorf("error during client registration: statusCode=%d, body=%s", status, body)
+	}
+	return i.parseDCRClient(body)
+}
+
+// DCRGet reads the registration of a client with its registration access
+// token.
+func (i Config) DCRGet() (DCRClient, error) {
+	if err := i.validateDCRClientConfig(); err != nil {
+		return DCRClient{}, fmt.Errorf("invalid parameters for client read: %w", err)
+	}
+	return i.dcrGet()
+}
+
+func (i Config) dcrGet() (DCRClient, error) {
+	body, status, err := i.dcrRequest(http.MethodGet, i.registrationClientURI(), i.RegistrationAccessToken, nil)
+	if err != nil {
+		return DCRClient{}, fmt.Errorf("error reading the client: %w", err)
+	}
+	if status != http.StatusOK {
+		return DCRClient{}, fmt.Errorf("error reading the client: statusCode=%d, body=%s", status, body)
+	}
+	return i.parseDCRClient(body)
+}
+
+// DCRUpdate replaces the client name, redirect URIs or scopes of a client
+// registration. The other metadata are read first and sent unchanged, as the
+// update replaces the whole registration.
+func (i Config) DCRUpdate() (DCRClient, error) {
+	err := i.validateDCRClientConfig()
+	if err == nil && i.ClientName == "" && len(i.RedirectURIs) == 0 && len(i.Scopes) == 0 {
+		err = fmt.Errorf("missing client name, redirect URIs or scopes parameter")
+	}
+	if err != nil {
+		return DCRClient{}, fmt.Errorf("invalid parameters for client update: %w", err)
+	}
+
+	current, err := i.dcrGet()
+	if err != nil {
+		return DCRClient{}, err
+	}
+	var metadata map[string]any
+	if err := json.Unmarshal([]byte(current.Body), &metadata); err != nil {
+		return DCRClient{}, fmt.Errorf("error parsing the client: %w", err)
+	}
+	for _, field := range dcrReadOnlyFields {
+		delete(metadata, field)
+	}
+	if i.ClientName != "" {
+		metadata["client_name"] = i.ClientName
+	}
+	if len(i.RedirectURIs) > 0 {
+		metadata["redirect_uris"] = i.RedirectURIs
+	}
+	if len(i.Scopes) > 0 {
+		metadata["scope"] = strings.Join(i.Scopes, " ")
+	}
+	payload, err := json.Marshal(metadata)
+	if err != nil {
+		return DCRClient{}, fmt.Errorf("error building the update payload: %w", err)
+	}
+
+	body, status, err := i.dcrRequest(http.MethodPut, i.registrationClientURI(), i.RegistrationAccessToken, payload)
+	if err != nil {
+		return DCRClient{}, fmt.Errorf("error updating the client: %w", err)
+	}
+	if status != http.StatusOK {
+		return DCRClient{}, fmt.Errorf("error updating the client: statusCode=%d, body=%s", status, body)
+	}
+	return i.parseDCRClient(body)
+}
+
+// DCRDelete deletes a client registration. Its registration access token and
+// credentials are no longer valid afterwards.
+func (i Config) DCRDelete() error {
+	if err := i.validateDCRClientConfig(); err != nil {
+		return fmt.Errorf("invalid parameters for client deletion: %w", err)
+	}
+
+	body, status, err := i.dcrRequest(http.MethodDelete, i.registrationClientURI(), i.RegistrationAccessToken, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting the client: %w", err)
+	}
+	if status != http.StatusNoContent && status != http.StatusOK {
+		return fmt.Errorf("error deleting the client: statusCode=%d, body=%s", status, body)
 	}
+	return nil
+}
 
-	return string(resp.Body), nil
+// registrationClientURI returns the client configuration endpoint: the URI
+// returned at registration, or the registration endpoint followed by the
+// client ID.
+func (i Config) registrationClientURI() string {
+	if i.RegistrationClientURI != "" {
+		return i.RegistrationClientURI
+	}
+	return strings.TrimRight(i.URL, "/") + "/ims/register/" + url.PathEscape(i.ClientID)
+}
+
+// dcrRequest sends a request to the registration or client configuration
+// endpoint, authenticated with the registration access token, if any. The HTTP
+// client is the one of the IMS client, with the same TLS, proxy, trace and
+// retry settings.
+func (i Config) dcrRequest(method, uri, token string, payload []byte) ([]byte, int, error) {
+	client, err := i.httpClient()
+	if err != nil {
+		return nil, 0, fmt.Errorf("error creating the HTTP client: %w", err)
+	}
+
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, uri, reqBody)
+	if err != nil {
+		return nil, 0, fmt.Errorf("create request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("perform request: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read response body: %w", err)
+	}
+	return body, res.StatusCode, nil
+}
+
+// parseDCRClient parses the response of the registration or client
+// configuration endpoint. The registration access token and URI of the
+// configuration are kept when the client configuration endpoint omits them,
+// as they are then unchanged.
+func (i Config) parseDCRClient(body []byte) (DCRClient, error) {
+	var client DCRClient
+	if err := json.Unmarshal(body, &client); err != nil {
+		return DCRClient{}, fmt.Errorf("error parsing the client registration: %w", err)
+	}
+	if client.ClientID == "" {
+		return DCRClient{}, fmt.Errorf("missing client_id in the client registration")
+	}
+	client.Body = string(body)
+
+	if i.RegistrationAccessToken == "" {
+		return client, nil
+	}
+	if client.RegistrationAccessToken == "" {
+		client.RegistrationAccessToken = i.RegistrationAccessToken
+	}
+	if client.RegistrationClientURI == "" {
+		client.RegistrationClientURI = i.registrationClientURI()
+	}
+	return client, nil
 }
@@ -0,0 +1,213 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// DCRMetadata is the client metadata sent at registration (RFC 7591, section
+// 2), also returned with the registered client.
+type DCRMetadata struct {
+	ClientName              string          `json:"client_name,omitempty"`
+	RedirectURIs            []string        `json:"redirect_uris,omitempty"`
+	Scope                   string          `json:"scope,omitempty"`
+	GrantTypes              []string        `json:"grant_types,omitempty"`
+	ResponseTypes           []string        `json:"response_types,omitempty"`
+	TokenEndpointAuthMethod string          `json:"token_endpoint_auth_method,omitempty"`
+	ClientURI               string          `json:"client_uri,omitempty"`
+	LogoURI                 string          `json:"logo_uri,omitempty"`
+	TosURI                  string          `json:"tos_uri,omitempty"`
+	PolicyURI               string          `json:"policy_uri,omitempty"`
+	Contacts                []string        `json:"contacts,omitempty"`
+	JWKSURI                 string          `json:"jwks_uri,omitempty"`
+	JWKS                    json.RawMessage `json:"jwks,omitempty"`
+	SoftwareID              string          `json:"software_id,omitempty"`
+	SoftwareVersion         string          `json:"software_version,omitempty"`
+	SoftwareStatement       string          `json:"software_statement,omitempty"`
+}
+
+// grantResponseTypes pairs the grant types using the authorization endpoint
+// with the response type they go with (RFC 7591, section 2.1).
+var grantResponseTypes = [][2]string{
+	{"authorization_code", "code"},
+	{"implicit", "token"},
+}
+
+// dcrGrantTypes are the grant types of RFC 7591. Extension grant types are
+// accepted as absolute URIs.
+var dcrGrantTypes = []string{
+	"authorization_code", "implicit", "password", "client_credentials", "refresh_token",
+}
+
+// dcrResponseTypes are the response types of RFC 7591 and OpenID Connect,
+// combined with spaces.
+var dcrResponseTypes = []string{"code", "token", "id_token", "none"}
+
+// dcrAuthMethods are the token endpoint authentication methods of RFC 7591,
+// OpenID Connect and RFC 8705.
+var dcrAuthMethods = []string{
+	"none", "client_secret_post", "client_secret_basic", "client_secret_jwt", "private_key_jwt",
+	"tls_client_auth", "self_signed_tls_client_auth",
+}
+
+// dcrMetadata returns the metadata of the registration: the metadata document
+// of the configuration, if any, overridden by the individual parameters.
+func (i Config) dcrMetadata() (DCRMetadata, error) {
+	var m DCRMetadata
+	if i.Metadata != "" {
+		var err error
+		if m, err = readDCRMetadata(i.Metadata); err != nil {
+			return DCRMetadata{}, err
+		}
+	}
+
+	override := func(value *string, param string) {
+		if param != "" {
+			*value = param
+		}
+	}
+	overrideList := func(value *[]string, param []string) {
+		if len(param) > 0 {
+			*value = param
+		}
+	}
+	override(&m.ClientName, i.ClientName)
+	overrideList(&m.RedirectURIs, i.RedirectURIs)
+	override(&m.Scope, strings.Join(i.Scopes, " "))
+	overrideList(&m.GrantTypes, i.GrantTypes)
+	overrideList(&m.ResponseTypes, i.ResponseTypes)
+	override(&m.TokenEndpointAuthMethod, i.TokenEndpointAuthMethod)
+	override(&m.ClientURI, i.ClientURI)
+	override(&m.LogoURI, i.LogoURI)
+	override(&m.TosURI, i.TosURI)
+	override(&m.PolicyURI, i.PolicyURI)
+	overrideList(&m.Contacts, i.Contacts)
+	override(&m.JWKSURI, i.JWKSURI)
+	override(&m.SoftwareID, i.SoftwareID)
+	override(&m.SoftwareVersion, i.SoftwareVersion)
+	override(&m.SoftwareStatement, i.SoftwareStatement)
+	return m, nil
+}
+
+// readDCRMetadata reads a JSON or YAML metadata document, with the field names
+// of RFC 7591. Unknown fields are rejected, as they are most likely typos.
+func readDCRMetadata(path string) (DCRMetadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return DCRMetadata{}, fmt.Errorf("unable to read the metadata file: %w", err)
+	}
+	// JSON being valid YAML, both are parsed as YAML and converted to JSON.
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return DCRMetadata{}, fmt.Errorf("unable to parse the metadata file: %w", err)
+	}
+	if data, err = json.Marshal(doc); err != nil {
+		return DCRMetadata{}, fmt.Errorf("unable to parse the metadata file: %w", err)
+	}
+
+	var m DCRMetadata
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return DCRMetadata{}, fmt.Errorf("invalid metadata file: %w", err)
+	}
+	return m, nil
+}
+
+// extended reports whether the metadata go beyond the client name, redirect
+// URIs and scope of the registration of the IMS client.
+func (m DCRMetadata) extended() bool {
+	basic := DCRMetadata{ClientName: m.ClientName, RedirectURIs: m.RedirectURIs, Scope: m.Scope}
+	return !reflect.DeepEqual(m, basic)
+}
+
+// validate checks the metadata before they are sent to IMS.
+func (m DCRMetadata) validate() error {
+	grantTypes := m.GrantTypes
+	if len(grantTypes) == 0 {
+		// The default of RFC 7591.
+		grantTypes = []string{"authorization_code"}
+	}
+	redirects := slices.Contains(grantTypes, "authorization_code") || slices.Contains(grantTypes, "implicit")
+
+	switch {
+	case m.ClientName == "":
+		return fmt.Errorf("missing client name parameter")
+	case len(m.RedirectURIs) == 0 && redirects:
+		return fmt.Errorf("missing redirect URIs parameter")
+	case m.JWKSURI != "" && len(m.JWKS) > 0:
+		return fmt.Errorf("jwks_uri and jwks are mutually exclusive")
+	case m.TokenEndpointAuthMethod != "" && !slices.Contains(dcrAuthMethods, m.TokenEndpointAuthMethod):
+		return fmt.Errorf("invalid token endpoint auth method %q, expected one of %s", m.TokenEndpointAuthMethod,
+			strings.Join(dcrAuthMethods, ", "))
+	case m.TokenEndpointAuthMethod == "private_key_jwt" && m.JWKSURI == "" && len(m.JWKS) == 0:
+		return fmt.Errorf("the private_key_jwt auth method requires jwks_uri or jwks")
+	}
+
+	for _, uri := range m.RedirectURIs {
+		u, err := url.Parse(uri)
+		if err != nil || !u.IsAbs() || u.Fragment != "" {
+			return fmt.Errorf("invalid redirect URI %q, expected an absolute URI without fragment", uri)
+		}
+	}
+	for _, u := range []struct{ name, uri string }{
+		{"client URI", m.ClientURI}, {"logo URI", m.LogoURI}, {"terms of service URI", m.TosURI},
+		{"policy URI", m.PolicyURI}, {"JWKS URI", m.JWKSURI},
+	} {
+		if u.uri != "" && !validateURL(u.uri) {
+			return fmt.Errorf("invalid %s %q", u.name, u.uri)
+		}
+	}
+	for _, t := range grantTypes {
+		if !slices.Contains(dcrGrantTypes, t) && !strings.Contains(t, ":") {
+			return fmt.Errorf("invalid grant type %q, expected one of %s or an extension URI", t,
+				strings.Join(dcrGrantTypes, ", "))
+		}
+	}
+	for _, rt := range m.ResponseTypes {
+		for _, part := range strings.Fields(rt) {
+			if !slices.Contains(dcrResponseTypes, part) {
+				return fmt.Errorf("invalid response type %q, expected a combination of %s", rt,
+					strings.Join(dcrResponseTypes, ", "))
+			}
+		}
+	}
+	if len(m.GrantTypes) > 0 && len(m.ResponseTypes) > 0 {
+		for _, pair := range grantResponseTypes {
+			grantType, responseType := pair[0], pair[1]
+			if slices.Contains(m.GrantTypes, grantType) != slices.ContainsFunc(m.ResponseTypes, func(rt string) bool {
+				return slices.Contains(strings.Fields(rt), responseType)
+			}) {
+				return fmt.Errorf("the %s grant type and the %s response type go together", grantType, responseType)
+			}
+		}
+	}
+	for _, contact := range m.Contacts {
+		if _, err := mail.ParseAddress(contact); err != nil {
+			return fmt.Errorf("invalid contact %q, expected an email address", contact)
+		}
+	}
+	if m.SoftwareStatement != "" && strings.Count(m.SoftwareStatement, ".") != 2 {
+		return fmt.Errorf("invalid software statement, expected a JWT")
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateDCRClientConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{name: "client ID", config: Config{URL: "https://ims.example.com", ClientID: "cid", RegistrationAccessToken: "rat"}},
+		{name: "client URI", config: Config{RegistrationClientURI: "https://ims.example.com/ims/register/cid", RegistrationAccessToken: "rat"}},
+		{name: "missing token", config: Config{URL: "https://ims.example.com", ClientID: "cid"}, wantErr: "missing registration access token"},
+		{name: "missing client", config: Config{URL: "https://ims.example.com", RegistrationAccessToken: "rat"}, wantErr: "missing clientID or registration client URI"},
+		{name: "invalid client URI", config: Config{RegistrationClientURI: "/cid", RegistrationAccessToken: "rat"}, wantErr: "invalid registration client URI"},
+		{name: "missing URL", config: Config{ClientID: "cid", RegistrationAccessToken: "rat"}, wantErr: "missing IMS base URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertError(t, tt.config.validateDCRClientConfig(), tt.wantErr)
+		})
+	}
+}
+
+func TestDCRRegister(t *testing.T) {
+	var sent map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/ims/register" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		sent = nil
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"client_id":"cid","client_name":"app","registration_access_token":"rat"}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	config := Config{URL: srv.URL, ClientName: "app", RedirectURIs: []string{"https://example.com/cb"},
+		Scopes: []string{"openid", "email"}, Timeout: 5}
+	got, err := config.DCRRegister()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sent) != 3 || sent["scope"] != "openid email" {
+		t.Errorf("sent %v, want the client name, redirect URIs and scope", sent)
+	}
+	if got.ClientID != "cid" || got.RegistrationAccessToken != "rat" || got.RegistrationClientURI != "" {
+		t.Errorf("got %+v, want the registered client", got)
+	}
+
+	config.GrantTypes = []string{"authorization_code", "refresh_token"}
+	if _, err := config.DCRRegister(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if grantTypes, _ := sent["grant_types"].([]any); len(grantTypes) != 2 {
+		t.Errorf("sent %v, want the grant types", sent)
+	}
+}
+
+func TestDCRUpdate(t *testing.T) {
+	current := `{"client_id":"cid","client_secret":"sec","client_name":"app","redirect_uris":["https://example.com/cb"],
+		"scope":"openid","logo_uri":"https://example.com/logo.png","client_id_issued_at":1,
+		"registration_access_token":"rat","registration_client_uri":"https://ims.example.com/ims/register/cid"}`
+	var sent map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ims/register/cid" || r.Header.Get("Authorization") != "Bearer rat" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		if r.Method == http.MethodPut {
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &sent)
+			_, _ = w.Write(body)
+			return
+		}
+		_, _ = io.WriteString(w, current)
+	}))
+	t.Cleanup(srv.Close)
+
+	config := Config{URL: srv.URL, ClientID: "cid", RegistrationAccessToken: "rat", ClientName: "renamed", Timeout: 5}
+	got, err := config.DCRUpdate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent["client_name"] != "renamed" || sent["logo_uri"] == nil || sent["client_secret"] != "sec" || sent["scope"] != "openid" {
+		t.Errorf("sent %v, want the new name and the other metadata unchanged", sent)
+	}
+	for _, field := range dcrReadOnlyFields {
+		if _, ok := sent[field]; ok {
+			t.Errorf("sent %v, want no %s", sent, field)
+		}
+	}
+	if got.ClientName != "renamed" || got.RegistrationAccessToken != "rat" || got.RegistrationClientURI != srv.URL+"/ims/register/cid" {
+		t.Errorf("got %+v, want the updated client with its registration access token and URI", got)
+	}
+
+	config.ClientName = ""
+	if _, err := config.DCRUpdate(); err == nil {
+		t.Error("expected an error without anything to update")
+	}
+}
+
+func TestDCRMetadata(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "metadata.yaml")
+	err := os.WriteFile(yamlFile, []byte(`client_name: from-file
+redirect_uris: [https://example.com/cb]
+grant_types: [authorization_code, refresh_token]
+contacts: [admin@example.com]
+jwks: {keys: []}
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typo := filepath.Join(dir, "typo.json")
+	if err := os.WriteFile(typo, []byte(`{"client_name": "app", "redirect_uri": "https://example.com/cb"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Config{Metadata: yamlFile, ClientName: "from-flag", Scopes: []string{"openid", "email"}}.dcrMetadata()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.ClientName != "from-flag" || m.Scope != "openid email" || len(m.GrantTypes) != 2 || string(m.JWKS) != `{"keys":[]}` {
+		t.Errorf("metadata = %+v, want the file overridden by the parameters", m)
+	}
+	if _, err := (Config{Metadata: typo}).dcrMetadata(); err == nil || !strings.Contains(err.Error(), `unknown field "redirect_uri"`) {
+		t.Errorf("error = %v, want an unknown field", err)
+	}
+
+	valid := DCRMetadata{ClientName: "app", RedirectURIs: []string{"https://example.com/cb"}}
+	tests := []struct {
+		name    string
+		modify  func(m *DCRMetadata)
+		wantErr string
+	}{
+		{name: "valid", modify: func(m *DCRMetadata) {}},
+		{name: "client credentials without redirect URIs", modify: func(m *DCRMetadata) {
+			m.RedirectURIs, m.GrantTypes = nil, []string{"client_credentials"}
+		}},
+		{name: "extension grant type", modify: func(m *DCRMetadata) {
+			m.GrantTypes = []string{"urn:ietf:params:oauth:grant-type:device_code"}
+		}},
+		{name: "missing redirect URIs", modify: func(m *DCRMetadata) { m.RedirectURIs = nil }, wantErr: "missing redirect URIs"},
+		{name: "relative redirect URI", modify: func(m *DCRMetadata) { m.RedirectURIs = []string{"/cb"} }, wantErr: "invalid redirect URI"},
+		{name: "invalid grant type", modify: func(m *DCRMetadata) { m.GrantTypes = []string{"magic"} }, wantErr: `invalid grant type "magic"`},
+		{name: "invalid response type", modify: func(m *DCRMetadata) { m.ResponseTypes = []string{"code magic"} }, wantErr: "invalid response type"},
+		{name: "inconsistent types", modify: func(m *DCRMetadata) {
+			m.GrantTypes, m.ResponseTypes = []string{"authorization_code"}, []string{"token"}
+		}, wantErr: "the authorization_code grant type and the code response type go together"},
+		{name: "invalid auth method", modify: func(m *DCRMetadata) { m.TokenEndpointAuthMethod = "magic" }, wantErr: "invalid token endpoint auth method"},
+		{name: "private key JWT without keys", modify: func(m *DCRMetadata) { m.TokenEndpointAuthMethod = "private_key_jwt" }, wantErr: "requires jwks_uri or jwks"},
+		{name: "both JWKS", modify: func(m *DCRMetadata) {
+			m.JWKSURI, m.JWKS = "https://example.com/jwks", []byte(`{"keys":[]}`)
+		}, wantErr: "mutually exclusive"},
+		{name: "invalid logo URI", modify: func(m *DCRMetadata) { m.LogoURI = "logo.png" }, wantErr: "invalid logo URI"},
+		{name: "invalid contact", modify: func(m *DCRMetadata) { m.Contacts = []string{"admin"} }, wantErr: "invalid contact"},
+		{name: "invalid software statement", modify: func(m *DCRMetadata) { m.SoftwareStatement = "abc" }, wantErr: "invalid software statement"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := valid
+			tt.modify(&m)
+			assertError(t, m.validate(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Encodings of the fulfillable_data of a product context. The payload is
+// always a base64 JSON document, gzipped or not.
+const (
+	FulfillableDataGzip   = "gzip"
+	FulfillableDataBase64 = "base64"
+	// FulfillableDataAuto detects whether the payload is gzipped.
+	FulfillableDataAuto = "auto"
+)
+
+// fulfillableDataErrorKey is added next to a fulfillable_data that could not
+// be decoded, which is kept as received.
+const fulfillableDataErrorKey = "fulfillable_data_error"
+
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// fulfillableDataEncodings turns the base64-decoded payload into JSON.
+var fulfillableDataEncodings = map[string]func([]byte) ([]byte, error){
+	FulfillableDataGzip:   gunzip,
+	FulfillableDataBase64: func(b []byte) ([]byte, error) { return b, nil },
+	FulfillableDataAuto: func(b []byte) ([]byte, error) {
+		if bytes.HasPrefix(b, gzipMagic) {
+			return gunzip(b)
+		}
+		return b, nil
+	},
+}
+
+// builtinFulfillableServiceCodes lists the service codes known to carry an
+// encoded fulfillable_data. More can be configured with
+// FulfillableDataDecoders.
+var builtinFulfillableServiceCodes = map[string]string{
+	"dma_media_library":  FulfillableDataGzip,
+	"dma_aem_cloud":      FulfillableDataGzip,
+	"dma_aem_contenthub": FulfillableDataGzip,
+	"dx_genstudio":       FulfillableDataGzip,
+}
+
+// fulfillableDataDecoders returns the encoding of the fulfillable_data of
+// each service code: the built-in ones, overridden by the configured ones.
+// Service codes are matched case-insensitively, the configuration file keys
+// being lowercased.
+func (i Config) fulfillableDataDecoders() (map[string]string, error) {
+	decoders := maps.Clone(builtinFulfillableServiceCodes)
+	for code, encoding := range i.FulfillableDataDecoders {
+		if _, ok := fulfillableDataEncodings[encoding]; !ok {
+			return nil, fmt.Errorf("invalid fulfillable data encoding %q for service code %q, expected one of %s",
+				encoding, code, strings.Join(slices.Sorted(maps.Keys(fulfillableDataEncodings)), ", "))
+		}
+		decoders[strings.ToLower(code)] = encoding
+	}
+	return decoders, nil
+}
+
+// decodeProfile replaces the fulfillable_data of the product contexts of the
+// profile with the decoded payload. An entry that cannot be decoded is kept
+// with the error next to it, without failing the whole profile.
+func decodeProfile(profile []byte, decoders map[string]string) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(profile))
+	dec.UseNumber()
+	var p any
+	if err := dec.Decode(&p); err != nil {
+		return "", fmt.Errorf("error parsing profile JSON: %w", err)
+	}
+
+	if failed := decodeFulfillableData(p, decoders); failed > 0 {
+		log.Printf("Unable to decode %d fulfillable_data entries, see %s in the profile.", failed, fulfillableDataErrorKey)
+	}
+
+	modifiedJson, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling JSON during profile decode: %w", err)
+	}
+	return string(modifiedJson), nil
+}
+
+// decodeFulfillableData walks the profile and decodes the fulfillable_data
+// of the objects whose serviceCode has a decoder. It returns the number of
+// entries that could not be decoded.
+func decodeFulfillableData(data any, decoders map[string]string) int {
+	failed := 0
+	switch data := data.(type) {
+	case map[string]any:
+		serviceCode, _ := data["serviceCode"].(string)
+		encoding, hasDecoder := decoders[strings.ToLower(serviceCode)]
+		for key, value := range data {
+			if key != "fulfillable_data" {
+				failed += decodeFulfillableData(value, decoders)
+				continue
+			}
+			encoded, ok := value.(string)
+			if !hasDecoder || !ok {
+				continue
+			}
+			decoded, err := decodeFulfillablePayload(encoded, encoding)
+			if err != nil {
+				data[fulfillableDataErrorKey] = err.Error()
+				failed++
+				continue
+			}
+			data[key] = decoded
+		}
+	case []any:
+		for _, item := range data {
+			failed += decodeFulfillableData(item, decoders)
+		}
+	}
+	return failed
+}
+
+// decodeFulfillablePayload decodes a base64 payload with the given encoding
+// into the JSON value it holds.
+func decodeFulfillablePayload(data, encoding string) (any, error) {
+	data = strings.TrimSpace(strings.Trim(data, "\""))
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		if raw, err = base64.RawStdEncoding.DecodeString(data); err != nil {
+			return nil, fmt.Errorf("unable to base64 decode fulfillable_data: %w", err)
+		}
+	}
+	payload, err := fulfillableDataEncodings[encoding](raw)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal the %s fulfillable_data: %w", encoding, err)
+	}
+	return v, nil
+}
+
+func gunzip(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create gzip reader: %w", err)
+	}
+	defer func() { _ = r.Close() }()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to gunzip fulfillable_data: %w", err)
+	}
+	return b, nil
+}
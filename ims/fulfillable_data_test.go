@@ -0,0 +1,118 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func gzipBase64(t *testing.T, s string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestFulfillableDataDecoders(t *testing.T) {
+	decoders, err := Config{FulfillableDataDecoders: map[string]string{
+		"My_Service":   FulfillableDataBase64,
+		"dx_genstudio": FulfillableDataAuto,
+	}}.fulfillableDataDecoders()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoders["my_service"] != FulfillableDataBase64 || decoders["dx_genstudio"] != FulfillableDataAuto ||
+		decoders["dma_aem_cloud"] != FulfillableDataGzip {
+		t.Errorf("decoders = %v", decoders)
+	}
+	if builtinFulfillableServiceCodes["dx_genstudio"] != FulfillableDataGzip {
+		t.Error("the built-in service codes must not be modified")
+	}
+
+	_, err = Config{FulfillableDataDecoders: map[string]string{"sc": "zip"}}.fulfillableDataDecoders()
+	assertError(t, err, `invalid fulfillable data encoding "zip" for service code "sc"`)
+}
+
+func TestDecodeProfile_FulfillableData(t *testing.T) {
+	payload := `{"iid":"instance-1","region":"va7","seats":12345678901234567}`
+	plain := base64.StdEncoding.EncodeToString([]byte(payload))
+	profile := `{"userId":"u1","projectedProductContext":[` +
+		`{"prodCtx":{"serviceCode":"dma_aem_cloud","fulfillable_data":"` + gzipBase64(t, payload) + `"}},` +
+		`{"prodCtx":{"serviceCode":"custom","fulfillable_data":"` + plain + `"}},` +
+		`{"prodCtx":{"serviceCode":"auto","fulfillable_data":"` + gzipBase64(t, payload) + `"}},` +
+		`{"prodCtx":{"serviceCode":"dma_media_library","fulfillable_data":"` + plain + `"}},` +
+		`{"prodCtx":{"serviceCode":"dx_genstudio","fulfillable_data":"not base64!"}},` +
+		`{"prodCtx":{"serviceCode":"other","fulfillable_data":"opaque"}}]}`
+	decoders, err := Config{FulfillableDataDecoders: map[string]string{
+		"custom": FulfillableDataBase64,
+		"auto":   FulfillableDataAuto,
+	}}.fulfillableDataDecoders()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := decodeProfile([]byte(profile), decoders)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got struct {
+		ProjectedProductContext []struct {
+			ProdCtx map[string]any `json:"prodCtx"`
+		} `json:"projectedProductContext"`
+	}
+	if err := json.Unmarshal([]byte(decoded), &got); err != nil {
+		t.Fatal(err)
+	}
+	ctx := got.ProjectedProductContext
+	if len(ctx) != 6 {
+		t.Fatalf("got %d product contexts, want 6", len(ctx))
+	}
+
+	for _, i := range []int{0, 1, 2} {
+		data, ok := ctx[i].ProdCtx["fulfillable_data"].(map[string]any)
+		if !ok || data["iid"] != "instance-1" || data["region"] != "va7" {
+			t.Errorf("%s: fulfillable_data = %v, want the whole payload", ctx[i].ProdCtx["serviceCode"], ctx[i].ProdCtx["fulfillable_data"])
+		}
+	}
+	if !strings.Contains(decoded, "12345678901234567") {
+		t.Error("large numbers must be kept as received")
+	}
+	// Failed entries keep their data and report the error, without stopping the others.
+	for _, tt := range []struct {
+		index   int
+		wantErr string
+	}{
+		{index: 3, wantErr: "unable to create gzip reader"},
+		{index: 4, wantErr: "unable to base64 decode"},
+	} {
+		prodCtx := ctx[tt.index].ProdCtx
+		if _, ok := prodCtx["fulfillable_data"].(string); !ok {
+			t.Errorf("%s: fulfillable_data = %v, want the encoded value", prodCtx["serviceCode"], prodCtx["fulfillable_data"])
+		}
+		if msg, _ := prodCtx[fulfillableDataErrorKey].(string); !strings.Contains(msg, tt.wantErr) {
+			t.Errorf("%s: %s = %q, want %q", prodCtx["serviceCode"], fulfillableDataErrorKey, msg, tt.wantErr)
+		}
+	}
+	if ctx[5].ProdCtx["fulfillable_data"] != "opaque" || ctx[5].ProdCtx[fulfillableDataErrorKey] != nil {
+		t.Errorf("other: %v, want the fulfillable_data untouched", ctx[5].ProdCtx)
+	}
+}
@@ -60,6 +60,11 @@ func (i Config) InvalidateToken() error {
 		return fmt.Errorf("error creating the IMS client: %w", err)
 	}
 
+	return i.invalidateToken(c)
+}
+
+// invalidateToken invalidates the token of the configuration with the given client.
+func (i Config) invalidateToken(c *ims.Client) error {
 	token, tokenType, err := i.resolveToken()
 	if err != nil {
 		return fmt.Errorf("unexpected error resolving token: %w", err)
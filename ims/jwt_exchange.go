@@ -12,8 +12,6 @@ package ims
 
 import (
 	"fmt"
-	"os"
-	"strings"
 	"time"
 
 	"github.com/adobe/ims-go/ims"
@@ -50,29 +48,27 @@ func (i Config) AuthorizeJWTExchange() (TokenInfo, error) {
 		return TokenInfo{}, fmt.Errorf("invalid parameters for JWT exchange: %w", err)
 	}
 
-	c, err := i.newIMSClient()
+	token, err := i.cachedAuthorization("jwt", i.exchangeJWT)
 	if err != nil {
-		return TokenInfo{}, fmt.Errorf("error creating the IMS client: %w", err)
+		return TokenInfo{}, err
 	}
 
-	key, err := os.ReadFile(i.PrivateKeyPath)
+	return TokenInfo{
+		AccessToken: token,
+	}, nil
+}
+
+func (i Config) exchangeJWT() (string, time.Duration, error) {
+	c, err := i.newIMSClient()
 	if err != nil {
-		return TokenInfo{}, fmt.Errorf("error reading private key file %s: %w", i.PrivateKeyPath, err)
+		return "", 0, fmt.Errorf("error creating the IMS client: %w", err)
 	}
-	defer func() {
-		for i := range key {
-			key[i] = 0
-		}
-	}()
-
-	// 	Metascopes are passed as generic claims with the format map[string]any,
-	//  where the strings are in the form: baseIMSUrl/s/metascope and the value is 'true'
 
-	baseURL := strings.TrimRight(i.URL, "/")
-	claims := make(map[string]any)
-	for _, metascope := range i.Metascopes {
-		claims[fmt.Sprintf("%s/s/%s", baseURL, metascope)] = true
+	key, err := i.privateKeyPEM()
+	if err != nil {
+		return "", 0, err
 	}
+	defer clear(key)
 
 	r, err := c.ExchangeJWT(&ims.ExchangeJWTRequest{
 		PrivateKey:   key,
@@ -81,14 +77,12 @@ func (i Config) AuthorizeJWTExchange() (TokenInfo, error) {
 		Subject:      i.Account,
 		ClientID:     i.ClientID,
 		ClientSecret: i.ClientSecret,
-		Claims:       claims,
+		Claims:       i.metascopeClaims(),
 		Resources:    i.Resource,
 	})
 	if err != nil {
-		return TokenInfo{}, fmt.Errorf("error exchanging JWT: %w", err)
+		return "", 0, fmt.Errorf("error exchanging JWT: %w", err)
 	}
 
-	return TokenInfo{
-		AccessToken: r.AccessToken,
-	}, nil
+	return r.AccessToken, r.ExpiresIn, nil
 }
@@ -0,0 +1,92 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/adobe/imscli/keys"
+	"golang.org/x/term"
+)
+
+var (
+	// promptedPassphrases keeps the passphrases typed by the user by key file,
+	// so long running commands like exec and agent only prompt once.
+	promptedPassphrases   = map[string]string{}
+	promptedPassphrasesMu sync.Mutex
+)
+
+// readPrivateKey reads the private key of the JWT Bearer flow. Encrypted keys
+// and PKCS #12 bundles are decrypted in memory with the configured passphrase,
+// or with a passphrase prompted on the terminal.
+func (i Config) readPrivateKey() (*keys.PrivateKey, error) {
+	data, err := os.ReadFile(i.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading private key file %s: %w", i.PrivateKeyPath, err)
+	}
+	defer clear(data)
+
+	prompted := ""
+	key, err := keys.ParsePrivateKey(data, func() (string, error) {
+		if i.PrivateKeyPassphrase != "" {
+			return i.PrivateKeyPassphrase, nil
+		}
+		promptedPassphrasesMu.Lock()
+		p, ok := promptedPassphrases[i.PrivateKeyPath]
+		promptedPassphrasesMu.Unlock()
+		if ok {
+			return p, nil
+		}
+		p, err := promptPassphrase()
+		prompted = p
+		return p, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error parsing private key file %s: %w", i.PrivateKeyPath, err)
+	}
+	if prompted != "" {
+		promptedPassphrasesMu.Lock()
+		promptedPassphrases[i.PrivateKeyPath] = prompted
+		promptedPassphrasesMu.Unlock()
+	}
+	return key, nil
+}
+
+// privateKeyPEM returns the private key as an unencrypted PEM block, the only
+// format ims-go accepts. The decrypted key is never written to disk.
+func (i Config) privateKeyPEM() ([]byte, error) {
+	key, err := i.readPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	defer keys.Zero(key.PrivateKey)
+	return keys.MarshalPrivateKey(key.PrivateKey, keys.FormatPKCS1, "")
+}
+
+// promptPassphrase reads the passphrase of the private key on the terminal,
+// the prompt goes to stderr.
+func promptPassphrase() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("%w: set --privateKeyPassphrase or IMS_PRIVATEKEYPASSPHRASE, or run in a terminal",
+			keys.ErrPassphraseRequired)
+	}
+	fmt.Fprint(os.Stderr, "Private key passphrase: ")
+	p, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("error reading the terminal: %w", err)
+	}
+	defer clear(p)
+	return string(p), nil
+}
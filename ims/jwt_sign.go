@@ -0,0 +1,115 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/adobe/imscli/keys"
+)
+
+func (i Config) validateSignJWTConfig() error {
+	switch {
+	case i.URL == "":
+		return fmt.Errorf("missing IMS base URL parameter")
+	case !validateURL(i.URL):
+		return fmt.Errorf("invalid IMS base URL parameter")
+	case i.ClientID == "":
+		return fmt.Errorf("missing client ID parameter")
+	case i.PrivateKeyPath == "":
+		return fmt.Errorf("missing private key path parameter")
+	case i.Organization == "":
+		return fmt.Errorf("missing organization parameter")
+	case i.Account == "":
+		return fmt.Errorf("missing account parameter")
+	default:
+		return nil
+	}
+}
+
+// SignJWT builds and signs the assertion of the JWT Bearer flow without
+// exchanging it, to debug the errors returned by IMS. The assertion is the
+// one ims-go sends during the exchange: same header, claims and RS256
+// signature, only the expiration depends on the time it is signed.
+func (i Config) SignJWT() (string, error) {
+	if err := i.validateSignJWTConfig(); err != nil {
+		return "", fmt.Errorf("invalid parameters for JWT signing: %w", err)
+	}
+
+	key, err := i.readPrivateKey()
+	if err != nil {
+		return "", err
+	}
+	defer keys.Zero(key.PrivateKey)
+
+	claims, err := i.jwtClaims(time.Now().Add(jwtExpiration))
+	if err != nil {
+		return "", err
+	}
+	return signRS256(key.PrivateKey, claims)
+}
+
+// jwtClaims returns the claims of the assertion, built like ims-go does: the
+// audience is derived from the IMS URL and the client ID, and each metascope
+// is a claim set to true.
+func (i Config) jwtClaims(expiration time.Time) (map[string]any, error) {
+	u, err := url.Parse(i.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IMS base URL parameter: %w", err)
+	}
+	claims := map[string]any{
+		"exp": expiration.Unix(),
+		"iss": i.Organization,
+		"sub": i.Account,
+		"aud": fmt.Sprintf("%s/c/%s", u.String(), i.ClientID),
+	}
+	for k, v := range i.metascopeClaims() {
+		claims[k] = v
+	}
+	return claims, nil
+}
+
+// metascopeClaims passes the metascopes as generic claims, in the form
+// baseIMSUrl/s/metascope with the value true.
+func (i Config) metascopeClaims() map[string]any {
+	baseURL := strings.TrimRight(i.URL, "/")
+	claims := make(map[string]any)
+	for _, metascope := range i.Metascopes {
+		claims[fmt.Sprintf("%s/s/%s", baseURL, metascope)] = true
+	}
+	return claims
+}
+
+// signRS256 encodes and signs a JWT. Claims are encoded with sorted keys, as
+// golang-jwt does for map claims.
+func signRS256(key *rsa.PrivateKey, claims map[string]any) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the JWT claims: %w", err)
+	}
+	enc := base64.RawURLEncoding
+	signed := enc.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`)) + "." + enc.EncodeToString(payload)
+	sum := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", fmt.Errorf("error signing the JWT: %w", err)
+	}
+	return signed + "." + enc.EncodeToString(sig), nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adobe/imscli/keys"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// decodeJWTPart decodes the header or the payload of a JWT.
+func decodeJWTPart(t *testing.T, part string) map[string]any {
+	t.Helper()
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestSignJWT(t *testing.T) {
+	withTempConfigDir(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := keys.MarshalPrivateKey(key, keys.FormatPKCS8, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), NotAfter: time.Now().Add(time.Hour)}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedCert, err := x509.ParseCertificate(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := pkcs12.Modern.Encode(key, parsedCert, nil, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFiles := map[string][]byte{
+		"pkcs1":           pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		"pkcs8":           pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+		"encrypted pkcs8": encrypted,
+		"pkcs12":          bundle,
+	}
+
+	// Capture the assertion sent by ims-go during the exchange.
+	var sent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = r.FormValue("jwt_token")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	for name, keyFile := range keyFiles {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "private.key")
+			if err := os.WriteFile(path, keyFile, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			config := Config{URL: srv.URL, ClientID: "client", ClientSecret: "secret", PrivateKeyPath: path,
+				PrivateKeyPassphrase: "passphrase", Organization: "org@AdobeOrg", Account: "account@techacct.adobe.com",
+				Metascopes: []string{"ent_dataservices_sdk"}, NoCache: true, Timeout: 5}
+
+			got, err := config.SignJWT()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			sent = ""
+			if _, err := config.AuthorizeJWTExchange(); err == nil {
+				t.Fatal("expected the exchange to fail")
+			}
+			if sent == "" {
+				t.Fatal("no assertion sent to IMS")
+			}
+
+			gotParts, sentParts := strings.Split(got, "."), strings.Split(sent, ".")
+			if len(gotParts) != 3 {
+				t.Fatalf("got %d parts, want 3", len(gotParts))
+			}
+			if gotParts[0] != sentParts[0] {
+				t.Errorf("header = %s, want %s", gotParts[0], sentParts[0])
+			}
+			gotClaims, sentClaims := decodeJWTPart(t, gotParts[1]), decodeJWTPart(t, sentParts[1])
+			delete(gotClaims, "exp")
+			delete(sentClaims, "exp")
+			if !reflect.DeepEqual(gotClaims, sentClaims) {
+				t.Errorf("claims = %v, want %v", gotClaims, sentClaims)
+			}
+			if gotClaims["aud"] != srv.URL+"/c/client" || gotClaims[srv.URL+"/s/ent_dataservices_sdk"] != true {
+				t.Errorf("unexpected claims %v", gotClaims)
+			}
+
+			sig, err := base64.RawURLEncoding.DecodeString(gotParts[2])
+			if err != nil {
+				t.Fatal(err)
+			}
+			sum := sha256.Sum256([]byte(gotParts[0] + "." + gotParts[1]))
+			if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, sum[:], sig); err != nil {
+				t.Errorf("invalid signature: %v", err)
+			}
+		})
+	}
+}
+
+func TestSignJWT_Errors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "private.key")
+	if err := os.WriteFile(path, []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := keys.MarshalPrivateKey(key, keys.FormatPKCS8, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encryptedPath := filepath.Join(dir, "encrypted.key")
+	if err := os.WriteFile(encryptedPath, encrypted, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{
+			name:    "missing account",
+			config:  Config{URL: "https://ims.example.com", ClientID: "c", PrivateKeyPath: path, Organization: "o"},
+			wantErr: "missing account parameter",
+		},
+		{
+			name:    "invalid key",
+			config:  Config{URL: "https://ims.example.com", ClientID: "c", PrivateKeyPath: path, Organization: "o", Account: "a"},
+			wantErr: "no PEM data found",
+		},
+		{
+			name: "missing passphrase",
+			config: Config{URL: "https://ims.example.com", ClientID: "c", PrivateKeyPath: encryptedPath,
+				Organization: "o", Account: "a"},
+			wantErr: "a passphrase is required",
+		},
+		{
+			name: "wrong passphrase",
+			config: Config{URL: "https://ims.example.com", ClientID: "c", PrivateKeyPath: encryptedPath,
+				PrivateKeyPassphrase: "wrong", Organization: "o", Account: "a"},
+			wantErr: "incorrect passphrase",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.config.SignJWT()
+			assertError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -11,24 +11,78 @@
 package ims
 
 import (
-	"bytes"
-	"compress/gzip"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
-	"strings"
 
 	"github.com/adobe/ims-go/ims"
 )
 
+// Profile is a user profile. The fields stable across the API versions are
+// parsed, the whole document is kept in Body.
+type Profile struct {
+	UserID                  string           `json:"userId"`
+	AuthID                  string           `json:"authId,omitempty"`
+	Email                   string           `json:"email,omitempty"`
+	EmailVerified           any              `json:"emailVerified,omitempty"`
+	Name                    string           `json:"name,omitempty"`
+	FirstName               string           `json:"first_name,omitempty"`
+	LastName                string           `json:"last_name,omitempty"`
+	DisplayName             string           `json:"displayName,omitempty"`
+	AccountType             string           `json:"account_type,omitempty"`
+	CountryCode             string           `json:"countryCode,omitempty"`
+	ProjectedProductContext []ProductContext `json:"projectedProductContext,omitempty"`
+	Roles                   []Role           `json:"roles,omitempty"`
+	// Body is the profile returned by IMS, with the fulfillable data decoded
+	// when requested.
+	Body string `json:"-"`
+}
+
+// ProductContext is an entry of the projected product context of a profile.
+type ProductContext struct {
+	ProdCtx struct {
+		ServiceCode  string `json:"serviceCode"`
+		ServiceLevel string `json:"serviceLevel,omitempty"`
+		OwningEntity string `json:"owningEntity,omitempty"`
+		// FulfillableData is the raw string, or the decoded payload.
+		FulfillableData any `json:"fulfillable_data,omitempty"`
+	} `json:"prodCtx"`
+}
+
+// Role is a role of the user in an organization.
+type Role struct {
+	Principal    string `json:"principal,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	Target       string `json:"target,omitempty"`
+	TargetType   string `json:"target_type,omitempty"`
+	NamedRole    string `json:"named_role"`
+	TargetData   any    `json:"target_data,omitempty"`
+}
+
+// parseProfile parses the stable fields of a profile document. A field of an
+// unexpected type does not hide the rest of the profile, only a malformed
+// document is an error.
+func parseProfile(body []byte) (Profile, error) {
+	if !json.Valid(body) {
+		return Profile{}, fmt.Errorf("error parsing the profile: invalid JSON document")
+	}
+	var p Profile
+	if err := json.Unmarshal(body, &p); err != nil {
+		log.Printf("Unable to parse all the profile fields: %v", err)
+	}
+	p.Body = string(body)
+	return p, nil
+}
+
 func (i Config) validateGetProfileConfig() error {
 	switch i.ProfileAPIVersion {
 	case "v1", "v2", "v3":
 	default:
 		return fmt.Errorf("invalid API version parameter, latest version is v3")
 	}
+	if _, err := i.fulfillableDataDecoders(); err != nil {
+		return err
+	}
 
 	switch {
 	case i.AccessToken == "":
@@ -44,16 +98,16 @@ func (i Config) validateGetProfileConfig() error {
 }
 
 // GetProfile requests the user profile using an access token.
-func (i Config) GetProfile() (string, error) {
+func (i Config) GetProfile() (Profile, error) {
 
 	err := i.validateGetProfileConfig()
 	if err != nil {
-		return "", fmt.Errorf("invalid parameters for profile: %w", err)
+		return Profile{}, fmt.Errorf("invalid parameters for profile: %w", err)
 	}
 
 	c, err := i.newIMSClient()
 	if err != nil {
-		return "", fmt.Errorf("error creating the IMS client: %w", err)
+		return Profile{}, fmt.Errorf("error creating the IMS client: %w", err)
 	}
 
 	profile, err := c.GetProfile(&ims.GetProfileRequest{
@@ -61,108 +115,21 @@ func (i Config) GetProfile() (string, error) {
 		ApiVersion:  i.ProfileAPIVersion,
 	})
 	if err != nil {
-		return "", fmt.Errorf("error getting profile: %w", err)
+		return Profile{}, fmt.Errorf("error getting profile: %w", err)
 	}
 
 	if !i.DecodeFulfillableData {
-		return string(profile.Body), nil
+		return parseProfile(profile.Body)
 	}
 
 	// Decode the fulfillable_data in the product context
-	decodedProfile, err := decodeProfile(profile.Body)
-	if err != nil {
-		return "", err
-	}
-	return decodedProfile, nil
-}
-
-func decodeProfile(profile []byte) (string, error) {
-	// Parse the profile JSON
-	var p map[string]any
-	err := json.Unmarshal(profile, &p)
-	if err != nil {
-		return "", fmt.Errorf("error parsing profile JSON: %w", err)
-	}
-	findFulfillableData(p)
-
-	modifiedJson, err := json.Marshal(p)
+	decoders, err := i.fulfillableDataDecoders()
 	if err != nil {
-		return "", fmt.Errorf("error marshaling JSON during profile decode: %w", err)
+		return Profile{}, err
 	}
-
-	return string(modifiedJson), nil
-}
-
-// fulfillableServiceCodes lists the service codes whose fulfillable_data field
-// contains a base64+gzip-encoded payload that can be decoded.
-var fulfillableServiceCodes = map[string]bool{
-	"dma_media_library":  true,
-	"dma_aem_cloud":      true,
-	"dma_aem_contenthub": true,
-	"dx_genstudio":       true,
-}
-
-func findFulfillableData(data any) {
-	switch data := data.(type) {
-	case map[string]any:
-		for key, value := range data {
-			if key == "fulfillable_data" {
-				serviceCode, ok := data["serviceCode"].(string)
-				if ok && fulfillableServiceCodes[serviceCode] {
-
-					strValue, ok := value.(string)
-					if !ok {
-						// Skip non-string fulfillable_data to avoid a panic
-						continue
-					}
-					decodedFulfillableData, err := modifyFulfillableData(strValue)
-					if err != nil {
-						log.Printf("Error decoding fulfillable_data: %v", err)
-						return
-					}
-					data["fulfillable_data"] = decodedFulfillableData
-				}
-			} else {
-				findFulfillableData(value)
-			}
-		}
-	case []any:
-		for _, item := range data {
-			findFulfillableData(item)
-		}
-	}
-}
-
-type fulfillableData struct {
-	Iid string `json:"iid"`
-}
-
-func modifyFulfillableData(data string) (string, error) {
-	strippedGzippedInstanceID := strings.Trim(data, "\"")
-	gzippedInstanceIDBytes, err := base64.StdEncoding.DecodeString(strippedGzippedInstanceID)
-	if err != nil {
-		return "", fmt.Errorf("unable to base64 decode fulfillable_data: %w", err)
-	}
-
-	gzipReader, err := gzip.NewReader(bytes.NewReader(gzippedInstanceIDBytes))
-	if err != nil {
-		return "", fmt.Errorf("unable to create gzip reader: %w", err)
-	}
-	defer func() {
-		if _, gzErr := io.Copy(io.Discard, gzipReader); gzErr != nil {
-			log.Printf("error while consuming the gzip reader: %v", gzErr)
-		}
-
-		if gzErr := gzipReader.Close(); gzErr != nil {
-			log.Printf("unable to close gzip reader: %v", gzErr)
-		}
-	}()
-
-	iidDecoder := json.NewDecoder(gzipReader)
-	instanceIdJson := fulfillableData{}
-	err = iidDecoder.Decode(&instanceIdJson)
+	decodedProfile, err := decodeProfile(profile.Body, decoders)
 	if err != nil {
-		return "", fmt.Errorf("unable to unmarshall the fulfillable_data: %w", err)
+		return Profile{}, err
 	}
-	return instanceIdJson.Iid, nil
+	return parseProfile([]byte(decodedProfile))
 }
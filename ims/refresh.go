@@ -60,5 +60,6 @@ func (i Config) Refresh() (RefreshInfo, error) {
 			AccessToken: r.AccessToken,
 		},
 		RefreshToken: r.RefreshToken,
+		ExpiresIn:    r.ExpiresIn,
 	}, nil
 }
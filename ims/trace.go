@@ -0,0 +1,438 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redacted replaces the secrets in the traces.
+const redacted = "REDACTED"
+
+// sensitiveParams are the form parameters, query parameters and JSON fields
+// holding secrets.
+var sensitiveParams = map[string]bool{
+	"client_secret": true,
+	"access_token":  true,
+	"refresh_token": true,
+	"id_token":      true,
+	"code":          true,
+	"code_verifier": true,
+	"device_code":   true,
+	"token":         true,
+	"user_token":    true,
+	"subject_token": true,
+	"jwt_token":     true,
+	"assertion":     true,
+	"password":      true,
+	// Bearer token of the DCR client configuration endpoint.
+	"registration_access_token": true,
+}
+
+var (
+	// traceOutput receives the dump of --trace. It is not the log output,
+	// discarded without --verbose.
+	traceOutput io.Writer = os.Stderr
+
+	// harLogs are the HAR files being written, by path. Every HTTP client of
+	// the process appends its entries to the same file.
+	harLogs   = map[string]*harLog{}
+	harLogsMu sync.Mutex
+)
+
+// traceTransport dumps the requests and responses to stderr and records them
+// in a HAR file, with the secrets redacted.
+type traceTransport struct {
+	next http.RoundTripper
+	dump io.Writer
+	har  *harLog
+}
+
+func (i Config) traceTransport(next http.RoundTripper) http.RoundTripper {
+	t := &traceTransport{next: next}
+	if i.Trace {
+		t.dump = traceOutput
+	}
+	if i.TraceHAR != "" {
+		harLogsMu.Lock()
+		t.har = harLogs[i.TraceHAR]
+		if t.har == nil {
+			t.har = &harLog{path: i.TraceHAR}
+			harLogs[i.TraceHAR] = t.har
+		}
+		harLogsMu.Unlock()
+	}
+	return t
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if reqBody != nil {
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	var resBody []byte
+	if err == nil {
+		resBody, err = io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		res.Body = io.NopCloser(bytes.NewReader(resBody))
+	}
+
+	reqBody = redactBody(req.Header.Get("Content-Type"), reqBody)
+	if err == nil {
+		resBody = redactBody(res.Header.Get("Content-Type"), resBody)
+	}
+	if t.dump != nil {
+		t.writeDump(req, reqBody, res, resBody, err, elapsed)
+	}
+	if t.har != nil {
+		t.har.add(newHAREntry(req, reqBody, res, resBody, err, start, elapsed))
+	}
+	return res, err
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	switch {
+	case req.Body == nil || req.Body == http.NoBody:
+		return nil, nil
+	case req.GetBody != nil:
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = body.Close() }()
+		return io.ReadAll(body)
+	default:
+		defer func() { _ = req.Body.Close() }()
+		return io.ReadAll(req.Body)
+	}
+}
+
+// writeDump writes the exchange in a single write, so the exchanges of
+// concurrent requests do not interleave.
+func (t *traceTransport) writeDump(req *http.Request, reqBody []byte, res *http.Response, resBody []byte,
+	err error, elapsed time.Duration) {
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "> %s %s %s\n", req.Method, redactURL(req.URL), req.Proto)
+	writeDumpHeaders(&b, "> ", req.Header)
+	writeDumpBody(&b, "> ", reqBody)
+	if err != nil {
+		fmt.Fprintf(&b, "< error after %s: %v\n", elapsed.Round(time.Millisecond), err)
+	} else {
+		fmt.Fprintf(&b, "< %s %s (%s)\n", res.Proto, res.Status, elapsed.Round(time.Millisecond))
+		writeDumpHeaders(&b, "< ", res.Header)
+		writeDumpBody(&b, "< ", resBody)
+	}
+	_, _ = io.WriteString(t.dump, b.String())
+}
+
+func writeDumpHeaders(b *strings.Builder, prefix string, header http.Header) {
+	for _, name := range sortedKeys(header) {
+		for _, value := range header[name] {
+			fmt.Fprintf(b, "%s%s: %s\n", prefix, name, redactHeader(name, value))
+		}
+	}
+}
+
+func writeDumpBody(b *strings.Builder, prefix string, body []byte) {
+	b.WriteString(strings.TrimSpace(prefix) + "\n")
+	if len(body) == 0 {
+		return
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(nil, len(body)+1)
+	for scanner.Scan() {
+		fmt.Fprintf(b, "%s%s\n", prefix, scanner.Text())
+	}
+}
+
+// redactHeader hides the credentials of the authorization and cookie headers,
+// and the secrets in the query of the redirection URLs.
+func redactHeader(name, value string) string {
+	switch http.CanonicalHeaderKey(name) {
+	case "Authorization", "Proxy-Authorization":
+		if scheme, _, ok := strings.Cut(value, " "); ok {
+			return scheme + " " + redacted
+		}
+		return redacted
+	case "Cookie", "Set-Cookie":
+		return redacted
+	case "Location":
+		if u, err := url.Parse(value); err == nil {
+			return redactURL(u)
+		}
+	}
+	return value
+}
+
+// redactURL hides the password of the URL and the sensitive query parameters.
+func redactURL(u *url.URL) string {
+	c := *u
+	c.RawQuery = redactQuery(u.RawQuery)
+	return c.Redacted()
+}
+
+// redactQuery hides the values of the sensitive parameters of a query or a
+// form, keeping the order of the parameters.
+func redactQuery(query string) string {
+	if query == "" {
+		return query
+	}
+	params := strings.Split(query, "&")
+	for n, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if k, err := url.QueryUnescape(key); err == nil && sensitiveParams[k] {
+			params[n] = key + "=" + redacted
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// redactBody hides the secrets of the form and JSON bodies.
+func redactBody(contentType string, body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		return []byte(redactQuery(string(body)))
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return body
+		}
+		if !redactJSON(v) {
+			return body
+		}
+		redactedBody, err := json.Marshal(v)
+		if err != nil {
+			return body
+		}
+		return redactedBody
+	default:
+		return body
+	}
+}
+
+// redactJSON replaces the values of the sensitive fields in place. It reports
+// whether a value was replaced.
+func redactJSON(v any) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]any:
+		for k, value := range v {
+			if _, ok := value.(string); ok && sensitiveParams[k] {
+				v[k] = redacted
+				changed = true
+			} else if redactJSON(value) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, value := range v {
+			if redactJSON(value) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+func sortedKeys(header http.Header) []string {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// harLog is a HAR 1.2 file, rewritten after each exchange so the trace is
+// complete even when imscli fails.
+type harLog struct {
+	path    string
+	mu      sync.Mutex
+	entries []harEntry
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Error           string      `json:"_error,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func newHAREntry(req *http.Request, reqBody []byte, res *http.Response, resBody []byte, err error,
+	start time.Time, elapsed time.Duration) harEntry {
+
+	ms := float64(elapsed.Microseconds()) / 1000
+	entry := harEntry{
+		StartedDateTime: start.Format(time.RFC3339Nano),
+		Time:            ms,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         redactURL(req.URL),
+			HTTPVersion: req.Proto,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(req.Header),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response: harResponse{
+			Cookies:     []harNameValue{},
+			Headers:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings: harTimings{Wait: ms},
+	}
+	if q, err := url.ParseQuery(redactQuery(req.URL.RawQuery)); err == nil {
+		for _, k := range sortedKeys(http.Header(q)) {
+			for _, v := range q[k] {
+				entry.Request.QueryString = append(entry.Request.QueryString, harNameValue{k, v})
+			}
+		}
+	}
+	if reqBody != nil {
+		entry.Request.PostData = &harPostData{MimeType: req.Header.Get("Content-Type"), Text: string(reqBody)}
+	}
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.Response.Status = res.StatusCode
+	entry.Response.StatusText = strings.TrimSpace(strings.TrimPrefix(res.Status, fmt.Sprint(res.StatusCode)))
+	entry.Response.HTTPVersion = res.Proto
+	entry.Response.Headers = harHeaders(res.Header)
+	entry.Response.RedirectURL = redactHeader("Location", res.Header.Get("Location"))
+	entry.Response.BodySize = len(resBody)
+	entry.Response.Content = harContent{
+		Size:     len(resBody),
+		MimeType: res.Header.Get("Content-Type"),
+		Text:     string(resBody),
+	}
+	return entry
+}
+
+func harHeaders(header http.Header) []harNameValue {
+	headers := []harNameValue{}
+	for _, name := range sortedKeys(header) {
+		for _, value := range header[name] {
+			headers = append(headers, harNameValue{name, redactHeader(name, value)})
+		}
+	}
+	return headers
+}
+
+// add appends an entry and rewrites the file.
+func (h *harLog) add(entry harEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = append(h.entries, entry)
+
+	version := "(devel)"
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		version = info.Main.Version
+	}
+	doc := map[string]any{
+		"log": map[string]any{
+			"version": "1.2",
+			"creator": map[string]string{"name": "imscli", "version": version},
+			"entries": h.entries,
+		},
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err == nil {
+		err = os.WriteFile(h.path, data, 0o600)
+	}
+	if err != nil {
+		log.Printf("Unable to write the HAR file %s: %v", h.path, err)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{
+			name:        "form",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "grant_type=authorization_code&client_id=cid&client_secret=s3cr3t&code=abc",
+			want:        "grant_type=authorization_code&client_id=cid&client_secret=REDACTED&code=REDACTED",
+		},
+		{
+			name:        "json",
+			contentType: "application/json;charset=utf-8",
+			body:        `{"access_token":"at","expires_in":86399,"nested":[{"refresh_token":"rt"}],"token_type":"bearer"}`,
+			want:        `{"access_token":"REDACTED","expires_in":86399,"nested":[{"refresh_token":"REDACTED"}],"token_type":"bearer"}`,
+		},
+		{
+			name:        "json without secrets",
+			contentType: "application/json",
+			body:        `{ "valid": true }`,
+			want:        `{ "valid": true }`,
+		},
+		{name: "text", contentType: "text/plain", body: "access_token=at", want: "access_token=at"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(redactBody(tt.contentType, []byte(tt.body))); got != tt.want {
+				t.Errorf("redactBody() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	tests := []struct{ name, value, want string }{
+		{"Authorization", "Bearer eyJhbGciOi", "Bearer REDACTED"},
+		{"Authorization", "opaque", "REDACTED"},
+		{"Set-Cookie", "session=abc", "REDACTED"},
+		{"Location", "http://localhost:8888/?code=abc&state=s", "http://localhost:8888/?code=REDACTED&state=s"},
+		{"Content-Type", "application/json", "application/json"},
+	}
+	for _, tt := range tests {
+		if got := redactHeader(tt.name, tt.value); got != tt.want {
+			t.Errorf("redactHeader(%s, %s) = %s, want %s", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestTraceTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"secret-access-token","expires_in":86399}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	var dump bytes.Buffer
+	traceOutput = &dump
+	t.Cleanup(func() { traceOutput = os.Stderr })
+	harPath := filepath.Join(t.TempDir(), "trace.har")
+
+	config := Config{Timeout: 5, Trace: true, TraceHAR: harPath}
+	client, err := config.httpClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	form := url.Values{"grant_type": {"client_credentials"}, "client_secret": {"secret-client-secret"}}
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/ims/token/v3", strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer secret-bearer")
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var body struct {
+		AccessToken string `json:"access_token"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&body)
+	_ = res.Body.Close()
+	if err != nil || body.AccessToken != "secret-access-token" {
+		t.Errorf("the response body must be left untouched, got %+v, %v", body, err)
+	}
+
+	har, err := os.ReadFile(harPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Log struct {
+			Entries []harEntry `json:"entries"`
+		} `json:"log"`
+	}
+	if err := json.Unmarshal(har, &doc); err != nil {
+		t.Fatalf("invalid HAR file: %v", err)
+	}
+	if len(doc.Log.Entries) != 1 || doc.Log.Entries[0].Response.Status != http.StatusOK {
+		t.Errorf("unexpected HAR entries %+v", doc.Log.Entries)
+	}
+
+	for name, trace := range map[string]string{"dump": dump.String(), "HAR": string(har)} {
+		for _, secret := range []string{"secret-access-token", "secret-client-secret", "secret-bearer"} {
+			if strings.Contains(trace, secret) {
+				t.Errorf("the %s contains %s:\n%s", name, secret, trace)
+			}
+		}
+		if !strings.Contains(trace, "grant_type=client_credentials") {
+			t.Errorf("the %s does not contain the request body:\n%s", name, trace)
+		}
+	}
+	if !strings.Contains(dump.String(), "> POST "+srv.URL+"/ims/token/v3 HTTP/1.1\n") {
+		t.Errorf("the dump does not contain the request line:\n%s", dump.String())
+	}
+}
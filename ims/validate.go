@@ -59,6 +59,11 @@ func (i Config) ValidateToken() (TokenInfo, error) {
 		return TokenInfo{}, fmt.Errorf("error creating the IMS client: %w", err)
 	}
 
+	return i.validateToken(c)
+}
+
+// validateToken validates the token of the configuration with the given client.
+func (i Config) validateToken(c *ims.Client) (TokenInfo, error) {
 	token, tokenType, err := i.resolveToken()
 	if err != nil {
 		return TokenInfo{}, fmt.Errorf("unexpected error resolving token: %w", err)
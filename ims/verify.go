@@ -0,0 +1,400 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+// Local verification of JWT signatures. The public keys are fetched from the
+// IMS JWKS endpoint, from the certificate referenced by an absolute x5u header,
+// or read from a local JWKS file, and cached on disk under the user
+// configuration directory (e.g. ~/.config/imscli/keys).
+
+package ims
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// jwksPath is the path of the IMS endpoint publishing the token signing keys.
+const jwksPath = "/ims/keys"
+
+// keyCacheTTL is how long fetched keys are reused before being fetched again.
+// A key not found in the cached document is always fetched again, to pick up
+// rotated keys.
+const keyCacheTTL = 24 * time.Hour
+
+// signatureHashes maps the supported JWT algorithms to their hash functions.
+var signatureHashes = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+}
+
+// TokenVerification is the result of the local verification of a JWT.
+type TokenVerification struct {
+	Valid          bool
+	SignatureValid bool
+	Algorithm      string
+	KeyID          string
+	KeySource      string
+	Expired        bool
+	NotYetValid    bool
+	ExpiresAt      time.Time
+	NotBefore      time.Time
+	IssuedAt       time.Time
+	Errors         []string
+}
+
+// tokenHeader holds the JOSE header fields used to find the signing key.
+type tokenHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	X5U       string `json:"x5u"`
+}
+
+// jsonWebKey is an RSA key of a JWKS document (RFC 7517).
+type jsonWebKey struct {
+	KeyType string   `json:"kty"`
+	KeyID   string   `json:"kid"`
+	N       string   `json:"n"`
+	E       string   `json:"e"`
+	X5C     []string `json:"x5c"`
+}
+
+// keyCacheEntry is a key document stored in the on-disk key cache.
+type keyCacheEntry struct {
+	Source    string    `json:"source"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Data      []byte    `json:"data"`
+}
+
+func (i Config) validateVerifyTokenConfig() error {
+	switch {
+	case i.Token == "":
+		return fmt.Errorf("missing token parameter")
+	case i.JWKS == "" && i.URL == "":
+		return fmt.Errorf("missing IMS base URL parameter")
+	case i.JWKS == "" && !validateURL(i.URL):
+		return fmt.Errorf("unable to parse URL parameter")
+	}
+	return nil
+}
+
+// VerifyToken verifies the signature of a JWT with the public key referenced
+// by its header and checks the exp, nbf and iat claims. A token that fails the
+// verification is not an error: the reasons are reported in the result.
+func (i Config) VerifyToken() (*TokenVerification, error) {
+	if err := i.validateVerifyTokenConfig(); err != nil {
+		return nil, fmt.Errorf("incomplete parameters for token verification: %w", err)
+	}
+	parts := strings.Split(i.Token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("the JWT is not composed by 3 parts")
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("error decoding token header: %w", err)
+	}
+	var header tokenHeader
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, fmt.Errorf("error parsing token header: %w", err)
+	}
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("error decoding token payload: %w", err)
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(payloadBytes, &claims); err != nil {
+		return nil, fmt.Errorf("error parsing token payload: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("error decoding token signature: %w", err)
+	}
+
+	hash, ok := signatureHashes[header.Algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signature algorithm %q", header.Algorithm)
+	}
+
+	result := &TokenVerification{Algorithm: header.Algorithm, KeyID: header.KeyID}
+	key, source, err := i.verificationKey(header)
+	if err != nil {
+		return nil, err
+	}
+	result.KeySource = source
+
+	h := hash.New()
+	h.Write([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, hash, h.Sum(nil), signature); err != nil {
+		result.Errors = append(result.Errors, "invalid signature")
+	} else {
+		result.SignatureValid = true
+	}
+
+	checkTimeClaims(result, claims, time.Now())
+	result.Valid = len(result.Errors) == 0
+	return result, nil
+}
+
+// checkTimeClaims reports the validity period of the token. Besides the
+// standard claims in seconds, IMS tokens carry created_at and expires_in in
+// milliseconds.
+func checkTimeClaims(result *TokenVerification, claims map[string]any, now time.Time) {
+	if exp, ok := numericClaim(claims["exp"]); ok {
+		result.ExpiresAt = time.Unix(exp, 0)
+	}
+	if nbf, ok := numericClaim(claims["nbf"]); ok {
+		result.NotBefore = time.Unix(nbf, 0)
+	}
+	if iat, ok := numericClaim(claims["iat"]); ok {
+		result.IssuedAt = time.Unix(iat, 0)
+	}
+	if createdAt, ok := numericClaim(claims["created_at"]); ok {
+		if result.IssuedAt.IsZero() {
+			result.IssuedAt = time.UnixMilli(createdAt)
+		}
+		if expiresIn, ok := numericClaim(claims["expires_in"]); ok && result.ExpiresAt.IsZero() {
+			result.ExpiresAt = time.UnixMilli(createdAt + expiresIn)
+		}
+	}
+
+	if !result.ExpiresAt.IsZero() && !now.Before(result.ExpiresAt) {
+		result.Expired = true
+		result.Errors = append(result.Errors, "token expired at "+result.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	if !result.NotBefore.IsZero() && now.Before(result.NotBefore) {
+		result.NotYetValid = true
+		result.Errors = append(result.Errors, "token not valid before "+result.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if !result.IssuedAt.IsZero() && now.Before(result.IssuedAt) {
+		result.Errors = append(result.Errors, "token issued in the future at "+result.IssuedAt.UTC().Format(time.RFC3339))
+	}
+}
+
+// verificationKey finds the public key that signed the token, returning it
+// with a description of where it was found.
+func (i Config) verificationKey(header tokenHeader) (*rsa.PublicKey, string, error) {
+	switch {
+	case i.JWKS != "" && !isHTTPURL(i.JWKS):
+		data, err := os.ReadFile(i.JWKS)
+		if err != nil {
+			return nil, "", fmt.Errorf("error reading JWKS file %s: %w", i.JWKS, err)
+		}
+		key, err := findJWK(data, header)
+		if err != nil {
+			return nil, "", fmt.Errorf("error in JWKS file %s: %w", i.JWKS, err)
+		}
+		return key, i.JWKS, nil
+	case i.JWKS == "" && isHTTPURL(header.X5U):
+		// The header is not trusted until the signature is verified, so the
+		// certificate is only fetched from the IMS host.
+		if !sameHost(header.X5U, i.URL) {
+			return nil, "", fmt.Errorf("untrusted x5u %s, the certificate is not hosted by %s", header.X5U, i.URL)
+		}
+		key, err := i.fetchKey(header.X5U, func(data []byte) (*rsa.PublicKey, error) {
+			return parseCertificateKey(data)
+		})
+		return key, header.X5U, err
+	default:
+		source := i.JWKS
+		if source == "" {
+			source = strings.TrimRight(i.URL, "/") + jwksPath
+		}
+		key, err := i.fetchKey(source, func(data []byte) (*rsa.PublicKey, error) {
+			return findJWK(data, header)
+		})
+		return key, source, err
+	}
+}
+
+func isHTTPURL(s string) bool {
+	return strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+}
+
+func sameHost(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return ua.Scheme == ub.Scheme && strings.EqualFold(ua.Host, ub.Host)
+}
+
+// fetchKey extracts a key from the document at the given URL, using the key
+// cache when possible. A cached document that does not yield the key is
+// fetched again.
+func (i Config) fetchKey(source string, extract func([]byte) (*rsa.PublicKey, error)) (*rsa.PublicKey, error) {
+	entry, err := loadKeyCacheEntry(source)
+	switch {
+	case err == nil && time.Since(entry.FetchedAt) < keyCacheTTL:
+		if key, err := extract(entry.Data); err == nil {
+			log.Printf("Using cached keys of %s.", source)
+			return key, nil
+		}
+		log.Printf("Key not found in the cached keys of %s, fetching them again.", source)
+	case err != nil && !errors.Is(err, os.ErrNotExist):
+		log.Printf("Ignoring unreadable key cache entry: %v", err)
+	}
+
+	client, err := i.httpClient()
+	if err != nil {
+		return nil, fmt.Errorf("error creating the HTTP client: %w", err)
+	}
+	res, err := client.Get(source)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching keys from %s: %w", source, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching keys from %s: %w", source, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching keys from %s: statusCode=%d, body=%s", source, res.StatusCode, data)
+	}
+
+	key, err := extract(data)
+	if err != nil {
+		return nil, fmt.Errorf("error in keys fetched from %s: %w", source, err)
+	}
+	if err := storeKeyCacheEntry(keyCacheEntry{Source: source, FetchedAt: time.Now(), Data: data}); err != nil {
+		log.Printf("Unable to store the keys in the cache: %v", err)
+	}
+	return key, nil
+}
+
+// findJWK returns the RSA key of a JWKS document matching the key ID of the
+// token. Tokens without a kid, like some IMS tokens, are matched by the x5u
+// file name without its extension.
+func findJWK(data []byte, header tokenHeader) (*rsa.PublicKey, error) {
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("error parsing JWKS: %w", err)
+	}
+
+	kid := header.KeyID
+	if kid == "" && header.X5U != "" {
+		kid = strings.TrimSuffix(filepath.Base(header.X5U), filepath.Ext(header.X5U))
+	}
+	for _, k := range jwks.Keys {
+		if k.KeyType != "RSA" || (kid != "" && k.KeyID != kid) {
+			continue
+		}
+		return k.publicKey()
+	}
+	if kid == "" {
+		return nil, fmt.Errorf("no RSA key found")
+	}
+	return nil, fmt.Errorf("no RSA key found with kid %s", kid)
+}
+
+func (k jsonWebKey) publicKey() (*rsa.PublicKey, error) {
+	if k.N == "" && len(k.X5C) > 0 {
+		der, err := base64.StdEncoding.DecodeString(k.X5C[0])
+		if err != nil {
+			return nil, fmt.Errorf("error decoding x5c of key %s: %w", k.KeyID, err)
+		}
+		return parseCertificateKey(der)
+	}
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding modulus of key %s: %w", k.KeyID, err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding exponent of key %s: %w", k.KeyID, err)
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 2 || exponent.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("invalid RSA key %s", k.KeyID)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+// parseCertificateKey returns the RSA key of a PEM or DER encoded X.509
+// certificate.
+func parseCertificateKey(data []byte) (*rsa.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+	cert, err := x509.ParseCertificate(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing certificate: %w", err)
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("the certificate does not hold an RSA key")
+	}
+	return key, nil
+}
+
+// keyCacheDir returns the directory holding the cached keys.
+func keyCacheDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find configuration directory: %w", err)
+	}
+	return filepath.Join(configDir, "imscli", "keys"), nil
+}
+
+func keyCacheName(source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:]) + ".json"
+}
+
+func loadKeyCacheEntry(source string) (keyCacheEntry, error) {
+	dir, err := keyCacheDir()
+	if err != nil {
+		return keyCacheEntry{}, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, keyCacheName(source)))
+	if err != nil {
+		return keyCacheEntry{}, err
+	}
+	var entry keyCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return keyCacheEntry{}, fmt.Errorf("error parsing cached keys of %s: %w", source, err)
+	}
+	return entry, nil
+}
+
+func storeKeyCacheEntry(entry keyCacheEntry) error {
+	dir, err := keyCacheDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error marshalling cached keys: %w", err)
+	}
+	return writeFileAtomic(dir, keyCacheName(entry.Source), data)
+}
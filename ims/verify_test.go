@@ -0,0 +1,198 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package ims
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// signJWT builds an RS256 token signed with the given key.
+func signJWT(t *testing.T, key *rsa.PrivateKey, header, payload string) string {
+	t.Helper()
+	enc := base64.RawURLEncoding
+	signed := enc.EncodeToString([]byte(header)) + "." + enc.EncodeToString([]byte(payload))
+	sum := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + enc.EncodeToString(sig)
+}
+
+// jwksDocument publishes the public key with the given key ID.
+func jwksDocument(key *rsa.PrivateKey, kid string) string {
+	enc := base64.RawURLEncoding
+	return fmt.Sprintf(`{"keys":[{"kty":"RSA","kid":%q,"n":%q,"e":%q}]}`, kid,
+		enc.EncodeToString(key.N.Bytes()), enc.EncodeToString(big.NewInt(int64(key.E)).Bytes()))
+}
+
+// newJWKSServer serves the JWKS document and counts the requests.
+func newJWKSServer(t *testing.T, jwks string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != jwksPath {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(jwks))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestVerifyToken(t *testing.T) {
+	withTempConfigDir(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, _ := newJWKSServer(t, jwksDocument(key, "key-1"))
+
+	now := time.Now()
+	header := `{"alg":"RS256","kid":"key-1"}`
+	valid := fmt.Sprintf(`{"exp":%d,"iat":%d}`, now.Add(time.Hour).Unix(), now.Unix())
+	tests := []struct {
+		name       string
+		token      string
+		wantValid  bool
+		wantSig    bool
+		wantErrors string
+		wantErr    string
+	}{
+		{name: "valid", token: signJWT(t, key, header, valid), wantValid: true, wantSig: true},
+		{
+			name:      "valid IMS token",
+			token:     signJWT(t, key, header, fmt.Sprintf(`{"created_at":"%d","expires_in":"86400000"}`, now.UnixMilli())),
+			wantValid: true, wantSig: true,
+		},
+		{
+			name:       "forged",
+			token:      signJWT(t, other, header, valid),
+			wantErrors: "invalid signature",
+		},
+		{
+			name:       "expired",
+			token:      signJWT(t, key, header, fmt.Sprintf(`{"exp":%d}`, now.Add(-time.Hour).Unix())),
+			wantSig:    true,
+			wantErrors: "token expired",
+		},
+		{
+			name:       "not yet valid",
+			token:      signJWT(t, key, header, fmt.Sprintf(`{"nbf":%d}`, now.Add(time.Hour).Unix())),
+			wantSig:    true,
+			wantErrors: "not valid before",
+		},
+		{
+			name:    "unknown key",
+			token:   signJWT(t, key, `{"alg":"RS256","kid":"key-2"}`, valid),
+			wantErr: "no RSA key found with kid key-2",
+		},
+		{
+			name:    "unsupported algorithm",
+			token:   fakeJWT(valid),
+			wantErr: "unsupported signature algorithm",
+		},
+		{
+			name:    "untrusted x5u",
+			token:   signJWT(t, key, `{"alg":"RS256","x5u":"https://attacker.example.com/key.cer"}`, valid),
+			wantErr: "untrusted x5u",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{URL: srv.URL, Token: tt.token, Timeout: 5}
+			got, err := config.VerifyToken()
+			assertError(t, err, tt.wantErr)
+			if err != nil {
+				return
+			}
+			if got.Valid != tt.wantValid || got.SignatureValid != tt.wantSig {
+				t.Errorf("valid = %v, signature valid = %v, want %v, %v", got.Valid, got.SignatureValid, tt.wantValid, tt.wantSig)
+			}
+			if !strings.Contains(strings.Join(got.Errors, ", "), tt.wantErrors) {
+				t.Errorf("errors = %v, want %q", got.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestVerifyToken_KeyCache(t *testing.T) {
+	withTempConfigDir(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, requests := newJWKSServer(t, jwksDocument(key, "key-1"))
+	config := Config{URL: srv.URL, Token: signJWT(t, key, `{"alg":"RS256","kid":"key-1"}`, `{}`), Timeout: 5}
+
+	for range 2 {
+		got, err := config.VerifyToken()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !got.Valid {
+			t.Fatalf("errors = %v, want a valid token", got.Errors)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", n)
+	}
+
+	// A key missing from the cached keys is fetched again.
+	config.Token = signJWT(t, key, `{"alg":"RS256","kid":"key-2"}`, `{}`)
+	if _, err := config.VerifyToken(); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("JWKS fetched %d times, want 2", n)
+	}
+}
+
+func TestVerifyToken_LocalJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, []byte(jwksDocument(key, "key-1")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// No URL is needed, the keys are read offline.
+	config := Config{JWKS: path, Token: signJWT(t, key, `{"alg":"RS256","kid":"key-1"}`, `{}`)}
+	got, err := config.VerifyToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Valid || got.KeySource != path {
+		t.Errorf("valid = %v, key source = %q, want true, %q", got.Valid, got.KeySource, path)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package keys
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// minKeyBits is the smallest key size accepted by Generate.
+const minKeyBits = 2048
+
+// GenerateOptions are the parameters of a key pair and its certificate.
+type GenerateOptions struct {
+	Bits       int
+	Format     string
+	Passphrase string
+	Subject    pkix.Name
+	Validity   time.Duration
+}
+
+// Generate creates an RSA private key and a matching self-signed certificate,
+// both PEM encoded, ready to be uploaded to the developer console and used to
+// sign the assertions of the JWT Bearer flow.
+func Generate(opts GenerateOptions) (keyPEM, certPEM []byte, err error) {
+	if opts.Bits < minKeyBits {
+		return nil, nil, fmt.Errorf("the key size must be at least %d bits", minKeyBits)
+	}
+	if opts.Validity <= 0 {
+		return nil, nil, fmt.Errorf("the certificate validity must be positive")
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, opts.Bits)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating the private key: %w", err)
+	}
+	defer Zero(key)
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating the serial number: %w", err)
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      opts.Subject,
+		NotBefore:    now,
+		NotAfter:     now.Add(opts.Validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating the certificate: %w", err)
+	}
+
+	keyPEM, err = MarshalPrivateKey(key, opts.Format, opts.Passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	return keyPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+// ParseSubject parses a distinguished name like "CN=name,O=Org,C=US". The
+// supported attributes are CN, O, OU, L, ST, C, STREET and POSTALCODE.
+func ParseSubject(s string) (pkix.Name, error) {
+	var name pkix.Name
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		attr, value, ok := strings.Cut(part, "=")
+		value = strings.TrimSpace(value)
+		if !ok || value == "" {
+			return pkix.Name{}, fmt.Errorf("invalid subject attribute %q, expected <name>=<value>", part)
+		}
+		switch strings.ToUpper(strings.TrimSpace(attr)) {
+		case "CN":
+			name.CommonName = value
+		case "O":
+			name.Organization = append(name.Organization, value)
+		case "OU":
+			name.OrganizationalUnit = append(name.OrganizationalUnit, value)
+		case "L":
+			name.Locality = append(name.Locality, value)
+		case "ST":
+			name.Province = append(name.Province, value)
+		case "C":
+			name.Country = append(name.Country, value)
+		case "STREET":
+			name.StreetAddress = append(name.StreetAddress, value)
+		case "POSTALCODE":
+			name.PostalCode = append(name.PostalCode, value)
+		default:
+			return pkix.Name{}, fmt.Errorf("unsupported subject attribute %q", attr)
+		}
+	}
+	if name.CommonName == "" {
+		return pkix.Name{}, fmt.Errorf("the subject must have a common name (CN)")
+	}
+	return name, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package keys
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// KeyInfo describes a private key.
+type KeyInfo struct {
+	Algorithm string `json:"algorithm"`
+	Bits      int    `json:"bits"`
+	Format    string `json:"format"`
+	Encrypted bool   `json:"encrypted"`
+	// Fingerprint is the SHA-256 digest of the public key.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// CertificateInfo describes a certificate.
+type CertificateInfo struct {
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SerialNumber string    `json:"serial_number"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after"`
+	Expired      bool      `json:"expired"`
+	SelfSigned   bool      `json:"self_signed"`
+	// Fingerprint is the SHA-256 digest of the certificate.
+	Fingerprint string `json:"fingerprint"`
+	// PublicKeyFingerprint is the SHA-256 digest of the public key, equal to
+	// the fingerprint of the matching private key.
+	PublicKeyFingerprint string `json:"public_key_fingerprint"`
+}
+
+// InspectKey parses a PEM encoded private key and describes it.
+func InspectKey(data []byte, passphrase PassphraseFunc) (KeyInfo, error) {
+	key, err := ParsePrivateKey(data, passphrase)
+	if err != nil {
+		return KeyInfo{}, err
+	}
+	defer Zero(key.PrivateKey)
+
+	fingerprint, err := publicKeyFingerprint(&key.PublicKey)
+	if err != nil {
+		return KeyInfo{}, err
+	}
+	return KeyInfo{
+		Algorithm:   "RSA",
+		Bits:        key.N.BitLen(),
+		Format:      key.Format,
+		Encrypted:   key.Encrypted,
+		Fingerprint: fingerprint,
+	}, nil
+}
+
+// InspectCertificate parses a PEM encoded certificate and describes it.
+func InspectCertificate(data []byte) (CertificateInfo, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return CertificateInfo{}, fmt.Errorf("no PEM encoded certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return CertificateInfo{}, fmt.Errorf("unable to parse the certificate: %w", err)
+	}
+	fingerprint, err := publicKeyFingerprint(cert.PublicKey)
+	if err != nil {
+		return CertificateInfo{}, err
+	}
+	sum := sha256.Sum256(cert.Raw)
+	return CertificateInfo{
+		Subject:              cert.Subject.String(),
+		Issuer:               cert.Issuer.String(),
+		SerialNumber:         formatFingerprint(cert.SerialNumber.Bytes()),
+		NotBefore:            cert.NotBefore.UTC(),
+		NotAfter:             cert.NotAfter.UTC(),
+		Expired:              time.Now().After(cert.NotAfter),
+		SelfSigned:           isSelfSigned(cert),
+		Fingerprint:          formatFingerprint(sum[:]),
+		PublicKeyFingerprint: fingerprint,
+	}, nil
+}
+
+// isSelfSigned reports whether the certificate is signed by its own key. Leaf
+// certificates are not CAs, so x509.Certificate.CheckSignatureFrom cannot be
+// used.
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) &&
+		cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
+
+func publicKeyFingerprint(pub any) (string, error) {
+	if _, ok := pub.(*rsa.PublicKey); !ok {
+		return "", fmt.Errorf("the public key is not an RSA key")
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return formatFingerprint(sum[:]), nil
+}
+
+// formatFingerprint formats a digest like OpenSSL: uppercase hexadecimal
+// bytes separated by colons.
+func formatFingerprint(b []byte) string {
+	parts := make([]string, len(b))
+	for i := range b {
+		parts[i] = strings.ToUpper(hex.EncodeToString(b[i : i+1]))
+	}
+	return strings.Join(parts, ":")
+}
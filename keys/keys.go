@@ -0,0 +1,181 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+// Package keys generates, reads and inspects the RSA private keys and
+// self-signed certificates of the JWT service accounts. Private keys are PEM
+// encoded PKCS #1 or PKCS #8 keys, the latter optionally encrypted with a
+// passphrase (PBES2), or PKCS #12 bundles.
+package keys
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// Formats of the private keys.
+const (
+	FormatPKCS1  = "pkcs1"
+	FormatPKCS8  = "pkcs8"
+	FormatPKCS12 = "pkcs12"
+)
+
+// PEM block types of the private keys.
+const (
+	pemTypePKCS1     = "RSA PRIVATE KEY"
+	pemTypePKCS8     = "PRIVATE KEY"
+	pemTypeEncrypted = "ENCRYPTED PRIVATE KEY"
+)
+
+var (
+	// ErrPassphraseRequired is returned when reading an encrypted key without
+	// a passphrase.
+	ErrPassphraseRequired = errors.New("the private key is encrypted, a passphrase is required")
+	// ErrIncorrectPassphrase is returned when an encrypted key cannot be
+	// decrypted with the given passphrase.
+	ErrIncorrectPassphrase = errors.New("incorrect passphrase for the private key")
+)
+
+// PassphraseFunc returns the passphrase of an encrypted private key. It is
+// only called when the key is encrypted.
+type PassphraseFunc func() (string, error)
+
+// PrivateKey is an RSA private key with the format it was read from.
+type PrivateKey struct {
+	*rsa.PrivateKey
+	Format    string
+	Encrypted bool
+}
+
+// ParsePrivateKey parses a PEM encoded RSA private key or a PKCS #12 bundle.
+// Encrypted PKCS #8 keys and bundles protected by a password are decrypted
+// with the passphrase returned by the given function, which may be nil for
+// unencrypted keys.
+func ParsePrivateKey(data []byte, passphrase PassphraseFunc) (*PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return parsePKCS12(data, passphrase)
+	}
+
+	switch block.Type {
+	case pemTypePKCS1:
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the private key: %w", err)
+		}
+		return &PrivateKey{PrivateKey: key, Format: FormatPKCS1}, nil
+	case pemTypeEncrypted:
+		if passphrase == nil {
+			return nil, ErrPassphraseRequired
+		}
+		p, err := passphrase()
+		if err != nil {
+			return nil, err
+		}
+		key, err := decryptPKCS8(block.Bytes, p)
+		if err != nil {
+			return nil, err
+		}
+		return &PrivateKey{PrivateKey: key, Format: FormatPKCS8, Encrypted: true}, nil
+	default:
+		// Some tools write PKCS #1 keys in PRIVATE KEY blocks, try both.
+		if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+			return &PrivateKey{PrivateKey: key, Format: FormatPKCS1}, nil
+		}
+		key, err := parsePKCS8(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return &PrivateKey{PrivateKey: key, Format: FormatPKCS8}, nil
+	}
+}
+
+// parsePKCS12 reads the private key of a PKCS #12 bundle. The passphrase is
+// only asked for when the bundle is protected by a password.
+func parsePKCS12(data []byte, passphrase PassphraseFunc) (*PrivateKey, error) {
+	var p string
+	parsed, _, _, err := pkcs12.DecodeChain(data, p)
+	if errors.Is(err, pkcs12.ErrIncorrectPassword) {
+		if passphrase == nil {
+			return nil, ErrPassphraseRequired
+		}
+		if p, err = passphrase(); err != nil {
+			return nil, err
+		}
+		parsed, _, _, err = pkcs12.DecodeChain(data, p)
+		if errors.Is(err, pkcs12.ErrIncorrectPassword) {
+			return nil, ErrIncorrectPassphrase
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("no PEM data found, and unable to parse a PKCS #12 bundle: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("the private key is not an RSA key")
+	}
+	return &PrivateKey{PrivateKey: key, Format: FormatPKCS12, Encrypted: p != ""}, nil
+}
+
+func parsePKCS8(der []byte) (*rsa.PrivateKey, error) {
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("the private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// MarshalPrivateKey PEM encodes a private key in the given format. PKCS #8
+// keys are encrypted when a passphrase is given.
+func MarshalPrivateKey(key *rsa.PrivateKey, format, passphrase string) ([]byte, error) {
+	var block *pem.Block
+	switch format {
+	case FormatPKCS1:
+		if passphrase != "" {
+			return nil, fmt.Errorf("only PKCS #8 keys can be encrypted")
+		}
+		block = &pem.Block{Type: pemTypePKCS1, Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	case FormatPKCS8:
+		if passphrase != "" {
+			encrypted, err := encryptPKCS8(key, passphrase)
+			if err != nil {
+				return nil, err
+			}
+			block = &pem.Block{Type: pemTypeEncrypted, Bytes: encrypted}
+			break
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding the private key: %w", err)
+		}
+		block = &pem.Block{Type: pemTypePKCS8, Bytes: der}
+	default:
+		return nil, fmt.Errorf("unknown private key format %q, use %s or %s", format, FormatPKCS1, FormatPKCS8)
+	}
+	defer clear(block.Bytes)
+	return pem.EncodeToMemory(block), nil
+}
+
+// Zero clears the secret values of a key once used.
+func Zero(key *rsa.PrivateKey) {
+	key.D.SetInt64(0)
+	for _, p := range key.Primes {
+		p.SetInt64(0)
+	}
+	key.Precomputed = rsa.PrecomputedValues{}
+}
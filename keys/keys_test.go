@@ -0,0 +1,178 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package keys
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/youmark/pkcs8"
+)
+
+func TestGenerate(t *testing.T) {
+	subject := pkix.Name{CommonName: "test", Organization: []string{"Acme"}}
+	for _, tt := range []struct {
+		format     string
+		passphrase string
+	}{
+		{format: FormatPKCS1},
+		{format: FormatPKCS8},
+		{format: FormatPKCS8, passphrase: "passphrase"},
+	} {
+		t.Run(tt.format+" "+tt.passphrase, func(t *testing.T) {
+			keyPEM, certPEM, err := Generate(GenerateOptions{Bits: 2048, Format: tt.format,
+				Passphrase: tt.passphrase, Subject: subject, Validity: 24 * time.Hour})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			passphrase := func() (string, error) { return tt.passphrase, nil }
+			key, err := InspectKey(keyPEM, passphrase)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := KeyInfo{Algorithm: "RSA", Bits: 2048, Format: tt.format, Encrypted: tt.passphrase != "",
+				Fingerprint: key.Fingerprint}
+			if key != want {
+				t.Errorf("key = %+v, want %+v", key, want)
+			}
+
+			cert, err := InspectCertificate(certPEM)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cert.Subject != "CN=test,O=Acme" || !cert.SelfSigned || cert.Expired {
+				t.Errorf("unexpected certificate %+v", cert)
+			}
+			if cert.PublicKeyFingerprint != key.Fingerprint {
+				t.Errorf("certificate public key %s, want %s", cert.PublicKeyFingerprint, key.Fingerprint)
+			}
+		})
+	}
+
+	if _, _, err := Generate(GenerateOptions{Bits: 2048, Format: FormatPKCS1, Passphrase: "p",
+		Subject: subject, Validity: time.Hour}); err == nil {
+		t.Error("expected an error encrypting a PKCS #1 key")
+	}
+	if _, _, err := Generate(GenerateOptions{Bits: 1024, Format: FormatPKCS8, Subject: subject,
+		Validity: time.Hour}); err == nil {
+		t.Error("expected an error with a 1024 bits key")
+	}
+}
+
+func TestParsePrivateKey_Encrypted(t *testing.T) {
+	keyPEM, _, err := Generate(GenerateOptions{Bits: 2048, Format: FormatPKCS8, Passphrase: "right",
+		Subject: pkix.Name{CommonName: "test"}, Validity: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParsePrivateKey(keyPEM, nil); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("no passphrase: error = %v, want ErrPassphraseRequired", err)
+	}
+	wrong := func() (string, error) { return "wrong", nil }
+	if _, err := ParsePrivateKey(keyPEM, wrong); !errors.Is(err, ErrIncorrectPassphrase) {
+		t.Errorf("wrong passphrase: error = %v, want ErrIncorrectPassphrase", err)
+	}
+	right := func() (string, error) { return "right", nil }
+	key, err := ParsePrivateKey(keyPEM, right)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := key.Validate(); err != nil {
+		t.Errorf("invalid key: %v", err)
+	}
+}
+
+func TestParsePrivateKey_EncryptedByOtherTools(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// OpenSSL still writes 3DES keys with -v2 des3.
+	der, err := pkcs8.MarshalPrivateKey(key, []byte("secret"), &pkcs8.Opts{
+		Cipher:  pkcs8.TripleDESCBC,
+		KDFOpts: pkcs8.PBKDF2Opts{SaltSize: 8, IterationCount: 2048, HMACHash: crypto.SHA1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: pemTypeEncrypted, Bytes: der})
+	got, err := ParsePrivateKey(keyPEM, func() (string, error) { return "secret", nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Equal(key) || !got.Encrypted || got.Format != FormatPKCS8 {
+		t.Errorf("got %+v, want the encrypted PKCS #8 key", got)
+	}
+
+	// A ciphertext not made of whole blocks is an error, not a panic.
+	var info struct {
+		Algorithm     pkix.AlgorithmIdentifier
+		EncryptedData []byte
+	}
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		t.Fatal(err)
+	}
+	info.EncryptedData = info.EncryptedData[:len(info.EncryptedData)-1]
+	if der, err = asn1.Marshal(info); err != nil {
+		t.Fatal(err)
+	}
+	truncated := pem.EncodeToMemory(&pem.Block{Type: pemTypeEncrypted, Bytes: der})
+	if _, err := ParsePrivateKey(truncated, func() (string, error) { return "secret", nil }); err == nil ||
+		!strings.Contains(err.Error(), "malformed") {
+		t.Errorf("truncated key: error = %v, want malformed", err)
+	}
+}
+
+func TestParseSubject(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    pkix.Name
+		wantErr string
+	}{
+		{input: "CN=imscli", want: pkix.Name{CommonName: "imscli"}},
+		{
+			input: " CN = name , O=Org, OU=Unit, C=US ",
+			want: pkix.Name{CommonName: "name", Organization: []string{"Org"},
+				OrganizationalUnit: []string{"Unit"}, Country: []string{"US"}},
+		},
+		{input: "O=Org", wantErr: "must have a common name"},
+		{input: "CN", wantErr: "invalid subject attribute"},
+		{input: "CN=name,X=1", wantErr: "unsupported subject attribute"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSubject(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("subject = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
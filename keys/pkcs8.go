@@ -0,0 +1,68 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package keys
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"fmt"
+	"strings"
+
+	"github.com/youmark/pkcs8"
+)
+
+// pkcs8Opts encrypts the keys written by imscli with PBES2, using
+// PBKDF2-HMAC-SHA256 and AES-256-CBC like OpenSSL does by default.
+var pkcs8Opts = &pkcs8.Opts{
+	Cipher: pkcs8.AES256CBC,
+	KDFOpts: pkcs8.PBKDF2Opts{
+		SaltSize:       16,
+		IterationCount: 600000,
+		HMACHash:       crypto.SHA256,
+	},
+}
+
+// encryptPKCS8 returns the DER encoded EncryptedPrivateKeyInfo of a key.
+func encryptPKCS8(key *rsa.PrivateKey, passphrase string) ([]byte, error) {
+	der, err := pkcs8.MarshalPrivateKey(key, []byte(passphrase), pkcs8Opts)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting the private key: %w", err)
+	}
+	return der, nil
+}
+
+// decryptPKCS8 decrypts a DER encoded EncryptedPrivateKeyInfo. Only PBES2 is
+// supported, the legacy PBES1 schemes are considered broken.
+func decryptPKCS8(der []byte, passphrase string) (key *rsa.PrivateKey, err error) {
+	// pkcs8 reads the key as unencrypted without a passphrase.
+	if passphrase == "" {
+		return nil, ErrIncorrectPassphrase
+	}
+	// pkcs8 panics on malformed ciphertexts, e.g. not made of whole blocks.
+	defer func() {
+		if r := recover(); r != nil {
+			key, err = nil, fmt.Errorf("malformed encrypted private key")
+		}
+	}()
+
+	parsed, _, err := pkcs8.ParsePrivateKey(der, []byte(passphrase))
+	switch {
+	case err != nil && strings.Contains(err.Error(), "incorrect password"):
+		return nil, ErrIncorrectPassphrase
+	case err != nil:
+		return nil, fmt.Errorf("unable to decrypt the private key: %w", err)
+	}
+	rsaKey, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("the private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
@@ -11,6 +11,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -25,6 +26,11 @@ func main() {
 	rootCmd := cmd.RootCmd(version)
 
 	if err := rootCmd.Execute(); err != nil {
+		// The exit code of the command run by exec is propagated silently.
+		var exitErr *cmd.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
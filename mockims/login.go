@@ -0,0 +1,297 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+// Browser login and device authorization. The fake login page asks for no
+// password: the user ID submitted with the form is logged in.
+
+package mockims
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+	deviceCodeLifetime  = 10 * time.Minute
+	devicePollInterval  = 5
+)
+
+// deviceAuthorization is a pending device authorization (RFC 8628).
+type deviceAuthorization struct {
+	deviceCode string
+	userCode   string
+	clientID   string
+	scopes     []string
+	expiresAt  time.Time
+	approved   bool
+	denied     bool
+	userID     string
+}
+
+// authorizeParams are the parameters of the authorize endpoint, carried by
+// the login form as hidden fields.
+var authorizeParams = []string{
+	"client_id", "response_type", "redirect_uri", "scope", "state", "code_challenge", "code_challenge_method",
+}
+
+var loginPage = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html><head><title>Mock IMS - Sign in</title></head>
+<body>
+<h1>Mock IMS</h1>
+<p>Client <b>{{.ClientID}}</b> requests access to: {{.Scope}}</p>
+<form method="post">
+{{range $name, $value := .Hidden}}<input type="hidden" name="{{$name}}" value="{{$value}}">
+{{end}}<label>User ID <input name="user_id" value="{{.UserID}}"></label>
+<button type="submit">Sign in</button>
+</form>
+</body></html>
+`))
+
+var devicePage = template.Must(template.New("device").Parse(`<!DOCTYPE html>
+<html><head><title>Mock IMS - Device authorization</title></head>
+<body>
+<h1>Mock IMS</h1>
+{{if .Message}}<p>{{.Message}}</p>
+{{else}}<form method="post">
+<label>Code <input name="user_code" value="{{.UserCode}}"></label>
+<label>User ID <input name="user_id" value="{{.UserID}}"></label>
+<button type="submit" name="action" value="approve">Approve</button>
+<button type="submit" name="action" value="deny">Deny</button>
+</form>
+{{end}}</body></html>
+`))
+
+// handleAuthorize shows the fake login page, or logs the default user in
+// right away with AutoLogin.
+func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if !s.checkAuthorizeRequest(w, q) {
+		return
+	}
+	if s.opts.AutoLogin {
+		s.completeLogin(w, r, q, s.opts.UserID)
+		return
+	}
+
+	hidden := map[string]string{}
+	for _, name := range authorizeParams {
+		if v := q.Get(name); v != "" {
+			hidden[name] = v
+		}
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = loginPage.Execute(w, map[string]any{
+		"ClientID": q.Get("client_id"),
+		"Scope":    q.Get("scope"),
+		"Hidden":   hidden,
+		"UserID":   s.opts.UserID,
+	})
+}
+
+// handleLogin receives the submitted login form.
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "malformed login form", http.StatusBadRequest)
+		return
+	}
+	params := url.Values{}
+	for _, name := range authorizeParams {
+		params.Set(name, r.PostForm.Get(name))
+	}
+	if !s.checkAuthorizeRequest(w, params) {
+		return
+	}
+	userID := r.PostForm.Get("user_id")
+	if userID == "" {
+		userID = s.opts.UserID
+	}
+	s.completeLogin(w, r, params, userID)
+}
+
+func (s *Server) checkAuthorizeRequest(w http.ResponseWriter, q url.Values) bool {
+	var problem string
+	switch {
+	case q.Get("client_id") == "":
+		problem = "missing client_id parameter"
+	case q.Get("scope") == "":
+		problem = "missing scope parameter"
+	case q.Get("redirect_uri") == "":
+		problem = "missing redirect_uri parameter"
+	case q.Get("response_type") != "code" && q.Get("response_type") != "token":
+		problem = "unsupported response_type " + q.Get("response_type")
+	case q.Get("code_challenge") != "" && q.Get("code_challenge_method") != "S256":
+		problem = "unsupported code_challenge_method " + q.Get("code_challenge_method")
+	}
+	if problem != "" {
+		http.Error(w, problem, http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// completeLogin redirects the browser back to the client with an
+// authorization code in the query, or an access token in the fragment for
+// the implicit grant.
+func (s *Server) completeLogin(w http.ResponseWriter, r *http.Request, q url.Values, userID string) {
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "malformed redirect_uri parameter", http.StatusBadRequest)
+		return
+	}
+	clientID := q.Get("client_id")
+	scopes := splitScopes(q.Get("scope"))
+
+	values := url.Values{}
+	if q.Get("state") != "" {
+		values.Set("state", q.Get("state"))
+	}
+	if q.Get("response_type") == "token" {
+		t, err := s.issue(AccessToken, clientID, userID, scopes, s.opts.TokenLifetime, "")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		values.Set("access_token", t.Value)
+		values.Set("token_type", "bearer")
+		values.Set("expires_in", strconv.Itoa(int(t.ExpiresAt.Sub(t.CreatedAt).Seconds())))
+		redirect.Fragment = values.Encode()
+	} else {
+		code, err := s.issue(AuthorizationCode, clientID, userID, scopes, 10*time.Minute, "")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s.mu.Lock()
+		code.codeChallenge = q.Get("code_challenge")
+		s.mu.Unlock()
+		query := redirect.Query()
+		for k, v := range values {
+			query[k] = v
+		}
+		query.Set("code", code.Value)
+		redirect.RawQuery = query.Encode()
+	}
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (s *Server) handleDeviceAuthorize(w http.ResponseWriter, r *http.Request) {
+	clientID := r.PostFormValue("client_id")
+	if err := s.authenticateClient(clientID, r.PostFormValue("client_secret"), true); err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid_client", err.Error())
+		return
+	}
+	scopes := splitScopes(r.PostFormValue("scope"))
+	if len(scopes) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid_scope", "missing scope parameter")
+		return
+	}
+
+	code := strings.ToUpper(randomString(4))
+	auth := &deviceAuthorization{
+		deviceCode: randomString(16),
+		userCode:   code[:4] + "-" + code[4:],
+		clientID:   clientID,
+		scopes:     scopes,
+		expiresAt:  s.now().Add(deviceCodeLifetime),
+		approved:   s.opts.AutoLogin,
+		userID:     s.opts.UserID,
+	}
+	s.mu.Lock()
+	s.devices[auth.deviceCode] = auth
+	s.mu.Unlock()
+
+	verify := baseURL(r) + "/ims/device/verify"
+	writeJSON(w, http.StatusOK, map[string]any{
+		"device_code":               auth.deviceCode,
+		"user_code":                 auth.userCode,
+		"verification_uri":          verify,
+		"verification_uri_complete": verify + "?user_code=" + url.QueryEscape(auth.userCode),
+		"expires_in":                int(deviceCodeLifetime.Seconds()),
+		"interval":                  devicePollInterval,
+	})
+}
+
+func (s *Server) handleDeviceVerify(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = devicePage.Execute(w, map[string]any{
+		"UserCode": r.URL.Query().Get("user_code"),
+		"UserID":   s.opts.UserID,
+	})
+}
+
+func (s *Server) handleDeviceApprove(w http.ResponseWriter, r *http.Request) {
+	userCode := strings.ToUpper(strings.TrimSpace(r.PostFormValue("user_code")))
+	userID := r.PostFormValue("user_id")
+	if userID == "" {
+		userID = s.opts.UserID
+	}
+
+	message := "Unknown or expired code."
+	s.mu.Lock()
+	for _, auth := range s.devices {
+		if auth.userCode != userCode || !s.now().Before(auth.expiresAt) {
+			continue
+		}
+		if r.PostFormValue("action") == "deny" {
+			auth.denied = true
+			message = "Access denied, you can close this window."
+		} else {
+			auth.approved = true
+			auth.userID = userID
+			message = "Device authorized, you can close this window."
+		}
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = devicePage.Execute(w, map[string]any{"Message": message})
+}
+
+// handleDeviceToken answers the polling of the device code grant.
+func (s *Server) handleDeviceToken(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	auth, ok := s.devices[r.PostFormValue("device_code")]
+	var state deviceAuthorization
+	if ok {
+		state = *auth
+	}
+	expired := ok && !s.now().Before(state.expiresAt)
+	if ok && (expired || state.denied || state.approved) {
+		delete(s.devices, state.deviceCode)
+	}
+	s.mu.Unlock()
+
+	switch {
+	case !ok || state.clientID != r.PostFormValue("client_id"):
+		writeError(w, http.StatusBadRequest, "invalid_grant", "unknown device_code")
+	case expired:
+		writeError(w, http.StatusBadRequest, "expired_token", "the device code expired")
+	case state.denied:
+		writeError(w, http.StatusBadRequest, "access_denied", "the user denied the authorization")
+	case !state.approved:
+		writeError(w, http.StatusBadRequest, "authorization_pending", "the user has not completed the authorization")
+	default:
+		s.issueTokens(w, state.clientID, state.userID, state.scopes, true, "")
+	}
+}
+
+// baseURL returns the URL the client used to reach the server.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,832 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+// Package mockims implements a fake IMS service for local development and
+// testing. It understands the API contract used by imscli (paths, parameters
+// and authentication) and issues real RS256 signed JWTs from a key generated
+// at startup, published through a JWKS endpoint. Issued tokens are tracked, so
+// validation, invalidation and the authenticated endpoints behave like IMS.
+//
+// Clients are not preconfigured: any client ID is accepted with any non-empty
+// secret, except for the clients registered through the DCR endpoint, whose
+// generated secret is enforced.
+package mockims
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Token types, as used by the IMS validate and invalidate endpoints.
+const (
+	AccessToken       = "access_token"
+	RefreshToken      = "refresh_token"
+	DeviceToken       = "device_token"
+	AuthorizationCode = "authorization_code"
+)
+
+// anyClient is the client ID of the tokens accepted from any client, like the
+// permanent authorization code of the service flow.
+const anyClient = "*"
+
+// Options configures the mock server. Zero values are replaced by defaults.
+type Options struct {
+	// TokenLifetime is the lifetime of the issued access tokens.
+	TokenLifetime time.Duration
+	// AutoLogin completes the browser logins and the device authorizations
+	// without user interaction, for unattended tests.
+	AutoLogin bool
+	// UserID, Email, Name and OrgID describe the user that logs in.
+	UserID string
+	Email  string
+	Name   string
+	OrgID  string
+}
+
+// Token is a token issued by the mock server.
+type Token struct {
+	ID          string
+	Type        string
+	Value       string
+	ClientID    string
+	UserID      string
+	Scopes      []string
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	Invalidated bool
+	// Parent is the ID of the token this one was derived from, e.g. the
+	// refresh token of an access token, used by cascading invalidations.
+	Parent string
+
+	codeChallenge string
+}
+
+// ClientMetadata is the metadata of a client registered through the DCR
+// endpoint (RFC 7591).
+type ClientMetadata struct {
+	ClientName              string          `json:"client_name"`
+	RedirectURIs            []string        `json:"redirect_uris,omitempty"`
+	Scope                   string          `json:"scope,omitempty"`
+	GrantTypes              []string        `json:"grant_types,omitempty"`
+	ResponseTypes           []string        `json:"response_types,omitempty"`
+	TokenEndpointAuthMethod string          `json:"token_endpoint_auth_method,omitempty"`
+	ClientURI               string          `json:"client_uri,omitempty"`
+	LogoURI                 string          `json:"logo_uri,omitempty"`
+	TosURI                  string          `json:"tos_uri,omitempty"`
+	PolicyURI               string          `json:"policy_uri,omitempty"`
+	Contacts                []string        `json:"contacts,omitempty"`
+	JWKSURI                 string          `json:"jwks_uri,omitempty"`
+	JWKS                    json.RawMessage `json:"jwks,omitempty"`
+	SoftwareID              string          `json:"software_id,omitempty"`
+	SoftwareVersion         string          `json:"software_version,omitempty"`
+	SoftwareStatement       string          `json:"software_statement,omitempty"`
+}
+
+// check returns the RFC 7591 error code and description of invalid metadata.
+func (m ClientMetadata) check() (string, string, bool) {
+	redirects := len(m.GrantTypes) == 0 || slices.Contains(m.GrantTypes, "authorization_code") ||
+		slices.Contains(m.GrantTypes, "implicit")
+	switch {
+	case m.ClientName == "":
+		return "invalid_client_metadata", "missing client_name", false
+	case redirects && len(m.RedirectURIs) == 0:
+		return "invalid_redirect_uri", "missing redirect_uris", false
+	case m.SoftwareStatement != "" && strings.Count(m.SoftwareStatement, ".") != 2:
+		return "invalid_software_statement", "malformed software_statement", false
+	default:
+		return "", "", true
+	}
+}
+
+// Client is a client registered through the DCR endpoint.
+type Client struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	ClientMetadata
+	IssuedAt int64 `json:"client_id_issued_at"`
+	// RegistrationAccessToken authenticates the reads, updates and deletions
+	// of the registration on RegistrationClientURI (RFC 7592).
+	RegistrationAccessToken string `json:"registration_access_token"`
+	RegistrationClientURI   string `json:"registration_client_uri"`
+}
+
+// Server is the mock IMS service. Use Handler to serve it.
+type Server struct {
+	opts Options
+	key  *rsa.PrivateKey
+	kid  string
+	now  func() time.Time
+
+	mu      sync.Mutex
+	tokens  map[string]*Token
+	clients map[string]*Client
+	devices map[string]*deviceAuthorization
+}
+
+// New creates a mock server with a newly generated signing key.
+func New(opts Options) (*Server, error) {
+	if opts.TokenLifetime <= 0 {
+		opts.TokenLifetime = 24 * time.Hour
+	}
+	if opts.UserID == "" {
+		opts.UserID = "MOCKUSER@AdobeID"
+	}
+	if opts.Email == "" {
+		opts.Email = "mock.user@example.com"
+	}
+	if opts.Name == "" {
+		opts.Name = "Mock User"
+	}
+	if opts.OrgID == "" {
+		opts.OrgID = "MOCKORG@AdobeOrg"
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("error generating the signing key: %w", err)
+	}
+	return &Server{
+		opts:    opts,
+		key:     key,
+		kid:     "mockims-key-" + randomString(4),
+		now:     time.Now,
+		tokens:  map[string]*Token{},
+		clients: map[string]*Client{},
+		devices: map[string]*deviceAuthorization{},
+	}, nil
+}
+
+// Handler returns the HTTP handler implementing the IMS endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ims/keys", s.handleKeys)
+	mux.HandleFunc("GET /ims/authorize/v1", s.handleAuthorize)
+	mux.HandleFunc("POST /ims/authorize/v1", s.handleLogin)
+	mux.HandleFunc("POST /ims/device/authorize/v1", s.handleDeviceAuthorize)
+	mux.HandleFunc("GET /ims/device/verify", s.handleDeviceVerify)
+	mux.HandleFunc("POST /ims/device/verify", s.handleDeviceApprove)
+	mux.HandleFunc("POST /ims/token/v2", s.handleToken)
+	mux.HandleFunc("POST /ims/token/v3", s.handleTokenV3)
+	mux.HandleFunc("POST /ims/token/v4", s.handleOBO)
+	mux.HandleFunc("POST /ims/exchange/v1/jwt", s.handleJWTExchange)
+	mux.HandleFunc("POST /ims/validate_token/v1", s.handleValidate)
+	mux.HandleFunc("POST /ims/invalidate_token/v2", s.handleInvalidate)
+	mux.HandleFunc("GET /ims/profile/{version}", s.handleProfile)
+	mux.HandleFunc("GET /ims/organizations/{version}", s.handleOrganizations)
+	mux.HandleFunc("POST /ims/admin_profile/{version}", s.handleAdminProfile)
+	mux.HandleFunc("POST /ims/admin_organizations/{version}", s.handleAdminOrganizations)
+	mux.HandleFunc("POST /ims/register", s.handleRegister)
+	mux.HandleFunc("GET /ims/register/{client_id}", s.handleClientRead)
+	mux.HandleFunc("PUT /ims/register/{client_id}", s.handleClientUpdate)
+	mux.HandleFunc("DELETE /ims/register/{client_id}", s.handleClientDelete)
+	return mux
+}
+
+// Tokens returns a snapshot of the issued tokens, sorted by creation.
+func (s *Server) Tokens() []Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens := make([]Token, 0, len(s.tokens))
+	for _, t := range s.tokens {
+		tokens = append(tokens, *t)
+	}
+	slices.SortFunc(tokens, func(a, b Token) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return tokens
+}
+
+// IssueServiceCode issues a permanent authorization code accepted from any
+// client, to be used with the service authorization flow.
+func (s *Server) IssueServiceCode(scopes []string) (string, error) {
+	t, err := s.issue(AuthorizationCode, anyClient, s.opts.UserID, scopes, 365*24*time.Hour, "")
+	if err != nil {
+		return "", err
+	}
+	return t.Value, nil
+}
+
+// issue creates and records a new signed token.
+func (s *Server) issue(typ, clientID, userID string, scopes []string, lifetime time.Duration, parent string) (*Token, error) {
+	now := s.now()
+	t := &Token{
+		ID:        randomString(16),
+		Type:      typ,
+		ClientID:  clientID,
+		UserID:    userID,
+		Scopes:    scopes,
+		CreatedAt: now,
+		ExpiresAt: now.Add(lifetime),
+		Parent:    parent,
+	}
+
+	header := map[string]any{"alg": "RS256", "kid": s.kid, "x5u": s.kid + ".cer", "typ": "JWT"}
+	claims := map[string]any{
+		"id":         t.ID,
+		"type":       typ,
+		"client_id":  clientID,
+		"user_id":    userID,
+		"as":         "mockims",
+		"scope":      strings.Join(scopes, ","),
+		"created_at": strconv.FormatInt(now.UnixMilli(), 10),
+		"expires_in": strconv.FormatInt(lifetime.Milliseconds(), 10),
+	}
+	value, err := s.sign(header, claims)
+	if err != nil {
+		return nil, err
+	}
+	t.Value = value
+
+	s.mu.Lock()
+	s.tokens[t.ID] = t
+	s.mu.Unlock()
+	return t, nil
+}
+
+func (s *Server) sign(header, claims map[string]any) (string, error) {
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", fmt.Errorf("error encoding token header: %w", err)
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("error encoding token claims: %w", err)
+	}
+	enc := base64.RawURLEncoding
+	signed := enc.EncodeToString(h) + "." + enc.EncodeToString(c)
+	sum := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", fmt.Errorf("error signing token: %w", err)
+	}
+	return signed + "." + enc.EncodeToString(sig), nil
+}
+
+// lookup returns the issued token with the given value, or an error telling
+// why it is not valid.
+func (s *Server) lookup(value string) (*Token, error) {
+	parts := strings.Split(value, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token")
+	}
+	var claims struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tokens[claims.ID]
+	switch {
+	case !ok || t.Value != value:
+		return nil, fmt.Errorf("unknown token")
+	case t.Invalidated:
+		return nil, fmt.Errorf("token invalidated")
+	case !s.now().Before(t.ExpiresAt):
+		return nil, fmt.Errorf("token expired")
+	}
+	return t, nil
+}
+
+// lookupType is lookup checking the type of the token.
+func (s *Server) lookupType(value, typ string) (*Token, error) {
+	t, err := s.lookup(value)
+	if err != nil {
+		return nil, err
+	}
+	if t.Type != typ {
+		return nil, fmt.Errorf("token is not of type %s", typ)
+	}
+	return t, nil
+}
+
+// invalidate marks the token as invalidated, and with cascading all the
+// tokens derived from it.
+func (s *Server) invalidate(t *Token, cascading bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.Invalidated = true
+	if !cascading {
+		return
+	}
+	for _, other := range s.tokens {
+		if other.Parent == t.ID {
+			other.Invalidated = true
+		}
+	}
+}
+
+// authenticateClient checks the credentials of a client. Registered clients
+// must present their secret; any other client is accepted with a non-empty
+// secret, or without one when allowPublic is set.
+func (s *Server) authenticateClient(clientID, secret string, allowPublic bool) error {
+	if clientID == "" {
+		return fmt.Errorf("missing client_id parameter")
+	}
+	s.mu.Lock()
+	c, registered := s.clients[clientID]
+	s.mu.Unlock()
+	switch {
+	case registered && c.ClientSecret != secret && !(allowPublic && secret == ""):
+		return fmt.Errorf("invalid client_secret")
+	case secret == "" && !allowPublic:
+		return fmt.Errorf("missing client_secret parameter")
+	}
+	return nil
+}
+
+func (s *Server) handleKeys(w http.ResponseWriter, r *http.Request) {
+	enc := base64.RawURLEncoding
+	e := big64(s.key.E)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": s.kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   enc.EncodeToString(s.key.N.Bytes()),
+			"e":   enc.EncodeToString(e),
+		}},
+	})
+}
+
+// tokenResponse writes the response of the token endpoints. The refresh token
+// and the ID token are optional.
+func (s *Server) tokenResponse(w http.ResponseWriter, access, refresh *Token) {
+	resp := map[string]any{
+		"access_token": access.Value,
+		"token_type":   "bearer",
+		"expires_in":   int(access.ExpiresAt.Sub(access.CreatedAt).Seconds()),
+		"userId":       access.UserID,
+		"scope":        strings.Join(access.Scopes, ","),
+	}
+	if refresh != nil {
+		resp["refresh_token"] = refresh.Value
+	}
+	if slices.Contains(access.Scopes, "openid") {
+		idToken, err := s.sign(map[string]any{"alg": "RS256", "kid": s.kid, "typ": "JWT"}, map[string]any{
+			"iss":   "mockims",
+			"sub":   access.UserID,
+			"aud":   access.ClientID,
+			"email": s.opts.Email,
+			"name":  s.opts.Name,
+			"iat":   access.CreatedAt.Unix(),
+			"exp":   access.ExpiresAt.Unix(),
+		})
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "server_error", err.Error())
+			return
+		}
+		resp["id_token"] = idToken
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// issueTokens issues an access token, and a refresh token when requested,
+// writing the token response.
+func (s *Server) issueTokens(w http.ResponseWriter, clientID, userID string, scopes []string, withRefresh bool, parent string) {
+	var refresh *Token
+	if withRefresh {
+		var err error
+		refresh, err = s.issue(RefreshToken, clientID, userID, scopes, 14*24*time.Hour, parent)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "server_error", err.Error())
+			return
+		}
+		parent = refresh.ID
+	}
+	access, err := s.issue(AccessToken, clientID, userID, scopes, s.opts.TokenLifetime, parent)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+	s.tokenResponse(w, access, refresh)
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	clientID := r.PostFormValue("client_id")
+	secret := r.PostFormValue("client_secret")
+	scopes := splitScopes(r.PostFormValue("scope"))
+
+	switch grant := r.PostFormValue("grant_type"); grant {
+	case "authorization_code":
+		code, err := s.lookupType(r.PostFormValue("code"), AuthorizationCode)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_grant", err.Error())
+			return
+		}
+		if code.ClientID != anyClient && code.ClientID != clientID {
+			writeError(w, http.StatusBadRequest, "invalid_grant", "code issued to another client")
+			return
+		}
+		verifier := r.PostFormValue("code_verifier")
+		if err := s.authenticateClient(clientID, secret, verifier != ""); err != nil {
+			writeError(w, http.StatusUnauthorized, "invalid_client", err.Error())
+			return
+		}
+		if code.codeChallenge != "" && pkceChallenge(verifier) != code.codeChallenge {
+			writeError(w, http.StatusBadRequest, "invalid_grant", "code_verifier does not match the code_challenge")
+			return
+		}
+		if len(scopes) == 0 {
+			scopes = code.Scopes
+		}
+		// Browser codes are single use, the permanent service codes are not.
+		if code.ClientID != anyClient {
+			s.invalidate(code, false)
+		}
+		s.issueTokens(w, clientID, code.UserID, scopes, true, "")
+	case "client_credentials":
+		if err := s.authenticateClient(clientID, secret, false); err != nil {
+			writeError(w, http.StatusUnauthorized, "invalid_client", err.Error())
+			return
+		}
+		s.issueTokens(w, clientID, clientID+"@AdobeID", scopes, false, "")
+	case "refresh_token":
+		if err := s.authenticateClient(clientID, secret, false); err != nil {
+			writeError(w, http.StatusUnauthorized, "invalid_client", err.Error())
+			return
+		}
+		refresh, err := s.lookupType(r.PostFormValue("refresh_token"), RefreshToken)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_grant", err.Error())
+			return
+		}
+		if refresh.ClientID != clientID {
+			writeError(w, http.StatusBadRequest, "invalid_grant", "refresh token issued to another client")
+			return
+		}
+		if len(scopes) == 0 {
+			scopes = refresh.Scopes
+		}
+		access, err := s.issue(AccessToken, clientID, refresh.UserID, scopes, s.opts.TokenLifetime, refresh.ID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "server_error", err.Error())
+			return
+		}
+		s.tokenResponse(w, access, refresh)
+	default:
+		writeError(w, http.StatusBadRequest, "unsupported_grant_type", fmt.Sprintf("unsupported grant_type %q", grant))
+	}
+}
+
+// handleTokenV3 serves the cluster access token exchange and the device code
+// grant, which share the v3 token endpoint.
+func (s *Server) handleTokenV3(w http.ResponseWriter, r *http.Request) {
+	switch grant := r.PostFormValue("grant_type"); grant {
+	case "cluster_at_exchange":
+		clientID := r.URL.Query().Get("client_id")
+		if err := s.authenticateClient(clientID, r.PostFormValue("client_secret"), false); err != nil {
+			writeError(w, http.StatusUnauthorized, "invalid_client", err.Error())
+			return
+		}
+		user, err := s.lookupType(r.PostFormValue("user_token"), AccessToken)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_token", err.Error())
+			return
+		}
+		userID := user.UserID
+		if id := r.PostFormValue("user_id"); id != "" {
+			userID = id
+		}
+		s.issueTokens(w, clientID, userID, splitScopes(r.PostFormValue("scope")), false, user.ID)
+	case deviceCodeGrantType:
+		s.handleDeviceToken(w, r)
+	default:
+		writeError(w, http.StatusBadRequest, "unsupported_grant_type", fmt.Sprintf("unsupported grant_type %q", grant))
+	}
+}
+
+func (s *Server) handleOBO(w http.ResponseWriter, r *http.Request) {
+	clientID := r.PostFormValue("client_id")
+	if err := s.authenticateClient(clientID, r.PostFormValue("client_secret"), false); err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid_client", err.Error())
+		return
+	}
+	subject, err := s.lookupType(r.PostFormValue("subject_token"), AccessToken)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_token", err.Error())
+		return
+	}
+	s.issueTokens(w, clientID, subject.UserID, splitScopes(r.PostFormValue("scope")), false, subject.ID)
+}
+
+// handleJWTExchange issues an access token for the technical account in the
+// sub claim of the JWT. The signature of the JWT is not checked, since the
+// mock does not know the public keys of the clients.
+func (s *Server) handleJWTExchange(w http.ResponseWriter, r *http.Request) {
+	clientID := r.PostFormValue("client_id")
+	if err := s.authenticateClient(clientID, r.PostFormValue("client_secret"), false); err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid_client", err.Error())
+		return
+	}
+	parts := strings.Split(r.PostFormValue("jwt_token"), ".")
+	if len(parts) != 3 {
+		writeError(w, http.StatusBadRequest, "invalid_token", "malformed jwt_token")
+		return
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_token", "malformed jwt_token")
+		return
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_token", "malformed jwt_token")
+		return
+	}
+	sub, _ := claims["sub"].(string)
+	exp, _ := claims["exp"].(float64)
+	switch {
+	case sub == "":
+		writeError(w, http.StatusBadRequest, "invalid_token", "missing sub claim")
+		return
+	case !s.now().Before(time.Unix(int64(exp), 0)):
+		writeError(w, http.StatusBadRequest, "invalid_token", "jwt_token expired")
+		return
+	}
+
+	var scopes []string
+	for claim, value := range claims {
+		if b, ok := value.(bool); ok && b && strings.Contains(claim, "/s/") {
+			scopes = append(scopes, claim[strings.LastIndex(claim, "/s/")+3:])
+		}
+	}
+	slices.Sort(scopes)
+	s.issueTokens(w, clientID, sub, scopes, false, "")
+}
+
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	t, err := s.lookupType(r.PostFormValue("token"), r.PostFormValue("type"))
+	if err == nil && t.ClientID != anyClient && t.ClientID != r.PostFormValue("client_id") {
+		err = fmt.Errorf("token issued to another client")
+	}
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]any{"valid": false, "reason": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"valid": true,
+		"token": map[string]any{
+			"id":         t.ID,
+			"type":       t.Type,
+			"client_id":  t.ClientID,
+			"user_id":    t.UserID,
+			"scope":      strings.Join(t.Scopes, ","),
+			"created_at": t.CreatedAt.UnixMilli(),
+			"expires_in": t.ExpiresAt.Sub(t.CreatedAt).Milliseconds(),
+		},
+	})
+}
+
+func (s *Server) handleInvalidate(w http.ResponseWriter, r *http.Request) {
+	typ := r.PostFormValue("token_type")
+	if typ == "service_token" {
+		typ = AccessToken
+	}
+	t, err := s.lookupType(r.PostFormValue("token"), typ)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_token", err.Error())
+		return
+	}
+	if t.ClientID != r.PostFormValue("client_id") {
+		writeError(w, http.StatusBadRequest, "invalid_client", "token issued to another client")
+		return
+	}
+	s.invalidate(t, r.PostFormValue("cascading") == "all")
+	w.WriteHeader(http.StatusOK)
+}
+
+// bearer returns the valid access token of the Authorization header.
+func (s *Server) bearer(w http.ResponseWriter, r *http.Request) (*Token, bool) {
+	value, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "missing bearer token")
+		return nil, false
+	}
+	t, err := s.lookupType(value, AccessToken)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid_token", err.Error())
+		return nil, false
+	}
+	return t, true
+}
+
+func (s *Server) profile(userID string) map[string]any {
+	return map[string]any{
+		"userId":                  userID,
+		"authId":                  userID,
+		"email":                   s.opts.Email,
+		"name":                    s.opts.Name,
+		"account_type":            "type1",
+		"countryCode":             "US",
+		"emailVerified":           "true",
+		"projectedProductContext": []any{},
+		"roles": []any{map[string]any{
+			"principal":    userID,
+			"organization": s.opts.OrgID,
+			"target":       s.opts.OrgID,
+			"target_type":  "TRG_ORG",
+			"named_role":   "user",
+		}},
+	}
+}
+
+func (s *Server) organizations() []any {
+	ident, authSrc, _ := strings.Cut(s.opts.OrgID, "@")
+	return []any{map[string]any{
+		"orgName": "Mock Organization",
+		"orgRef":  map[string]any{"ident": ident, "authSrc": authSrc},
+		"orgType": "Enterprise",
+		"groups":  []any{},
+	}}
+}
+
+func (s *Server) handleProfile(w http.ResponseWriter, r *http.Request) {
+	if t, ok := s.bearer(w, r); ok {
+		writeJSON(w, http.StatusOK, s.profile(t.UserID))
+	}
+}
+
+func (s *Server) handleOrganizations(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.bearer(w, r); ok {
+		writeJSON(w, http.StatusOK, s.organizations())
+	}
+}
+
+func (s *Server) handleAdminProfile(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.bearer(w, r); !ok {
+		return
+	}
+	guid := r.PostFormValue("guid")
+	if guid == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "missing guid parameter")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.profile(guid))
+}
+
+func (s *Server) handleAdminOrganizations(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.bearer(w, r); !ok {
+		return
+	}
+	if r.PostFormValue("guid") == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "missing guid parameter")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.organizations())
+}
+
+func (s *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
+	var req ClientMetadata
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_client_metadata", "malformed registration request")
+		return
+	}
+	if code, description, ok := req.check(); !ok {
+		writeError(w, http.StatusBadRequest, code, description)
+		return
+	}
+
+	c := &Client{
+		ClientID:       "mock-" + randomString(8),
+		ClientSecret:   "p8e-" + randomString(16),
+		ClientMetadata: req,
+		IssuedAt:       s.now().Unix(),
+	}
+	c.RegistrationAccessToken = "reg-" + randomString(16)
+	c.RegistrationClientURI = baseURL(r) + "/ims/register/" + c.ClientID
+	s.mu.Lock()
+	s.clients[c.ClientID] = c
+	s.mu.Unlock()
+	writeJSON(w, http.StatusCreated, c)
+}
+
+// registeredClient returns the client of the configuration endpoint, checking
+// its registration access token.
+func (s *Server) registeredClient(w http.ResponseWriter, r *http.Request) (*Client, bool) {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	s.mu.Lock()
+	c, ok := s.clients[r.PathValue("client_id")]
+	s.mu.Unlock()
+	if !ok || token == "" || token != c.RegistrationAccessToken {
+		// Unknown clients are not disclosed (RFC 7592, section 2).
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid registration access token")
+		return nil, false
+	}
+	return c, true
+}
+
+func (s *Server) handleClientRead(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.registeredClient(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, c)
+}
+
+func (s *Server) handleClientUpdate(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.registeredClient(w, r)
+	if !ok {
+		return
+	}
+	var req struct {
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+		ClientMetadata
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_client_metadata", "malformed update request")
+		return
+	}
+	switch {
+	case req.ClientID != c.ClientID:
+		writeError(w, http.StatusBadRequest, "invalid_client_metadata", "client_id does not match")
+		return
+	case req.ClientSecret != "" && req.ClientSecret != c.ClientSecret:
+		writeError(w, http.StatusBadRequest, "invalid_client_metadata", "client_secret does not match")
+		return
+	}
+	if code, description, ok := req.check(); !ok {
+		writeError(w, http.StatusBadRequest, code, description)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c.ClientMetadata = req.ClientMetadata
+	writeJSON(w, http.StatusOK, c)
+}
+
+func (s *Server) handleClientDelete(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.registeredClient(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	delete(s.clients, c.ClientID)
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an OAuth error response, as parsed by ims-go.
+func writeError(w http.ResponseWriter, status int, code, description string) {
+	writeJSON(w, status, map[string]string{"error": code, "error_description": description})
+}
+
+// splitScopes accepts comma and space separated scopes.
+func splitScopes(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// pkceChallenge computes the S256 code challenge of a code verifier.
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomString(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// big64 encodes the RSA public exponent as big-endian bytes.
+func big64(e int) []byte {
+	var b []byte
+	for ; e > 0; e >>= 8 {
+		b = append([]byte{byte(e)}, b...)
+	}
+	return b
+}
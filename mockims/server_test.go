@@ -0,0 +1,277 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package mockims
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/adobe/imscli/ims"
+)
+
+func newTestServer(t *testing.T, opts Options) (*Server, *httptest.Server) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	s, err := New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	return s, srv
+}
+
+// postForm posts a form and decodes the JSON response.
+func postForm(t *testing.T, endpoint string, form url.Values) (int, map[string]any) {
+	t.Helper()
+	res, err := http.PostForm(endpoint, form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	var body map[string]any
+	_ = json.NewDecoder(res.Body).Decode(&body)
+	return res.StatusCode, body
+}
+
+func TestClientCredentialsLifecycle(t *testing.T) {
+	s, srv := newTestServer(t, Options{})
+	config := ims.Config{URL: srv.URL, ClientID: "cid", ClientSecret: "secret", Scopes: []string{"openid"},
+		Timeout: 5, NoCache: true}
+
+	token, err := config.AuthorizeClientCredentials()
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+
+	config.AccessToken = token
+	info, err := config.ValidateToken()
+	if err != nil || !info.Valid {
+		t.Fatalf("validate: valid = %v, err = %v, want a valid token", info.Valid, err)
+	}
+
+	verification, err := ims.Config{URL: srv.URL, Token: token, Timeout: 5}.VerifyToken()
+	if err != nil || !verification.Valid {
+		t.Fatalf("verify: %+v, err = %v, want a valid signature", verification, err)
+	}
+
+	config.ProfileAPIVersion = "v1"
+	if _, err := config.GetProfile(); err != nil {
+		t.Errorf("profile: unexpected error: %v", err)
+	}
+
+	if err := config.InvalidateToken(); err != nil {
+		t.Fatalf("invalidate: %v", err)
+	}
+	info, err = config.ValidateToken()
+	if err != nil || info.Valid {
+		t.Errorf("validate after invalidation: valid = %v, err = %v, want an invalid token", info.Valid, err)
+	}
+	if _, err := config.GetProfile(); err == nil {
+		t.Error("profile: expected an error with an invalidated token")
+	}
+
+	tokens := s.Tokens()
+	if len(tokens) != 1 || !tokens[0].Invalidated || tokens[0].ClientID != "cid" {
+		t.Errorf("tokens = %+v, want one invalidated token of client cid", tokens)
+	}
+}
+
+func TestAuthorizationCodeWithPKCE(t *testing.T) {
+	_, srv := newTestServer(t, Options{AutoLogin: true})
+	verifier := strings.Repeat("v", 43)
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	q := url.Values{
+		"client_id":             {"public"},
+		"response_type":         {"code"},
+		"redirect_uri":          {"http://localhost:8888/callback"},
+		"scope":                 {"openid,AdobeID"},
+		"state":                 {"xyz"},
+		"code_challenge":        {pkceChallenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+	res, err := client.Get(srv.URL + "/ims/authorize/v1?" + q.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+	location, err := url.Parse(res.Header.Get("Location"))
+	if err != nil || res.StatusCode != http.StatusFound {
+		t.Fatalf("status = %d, location = %v, want a redirect", res.StatusCode, res.Header.Get("Location"))
+	}
+	if location.Query().Get("state") != "xyz" {
+		t.Errorf("state = %q, want xyz", location.Query().Get("state"))
+	}
+	code := location.Query().Get("code")
+
+	form := url.Values{"grant_type": {"authorization_code"}, "client_id": {"public"}, "code": {code}}
+	form.Set("code_verifier", "wrong")
+	if status, _ := postForm(t, srv.URL+"/ims/token/v2", form); status != http.StatusBadRequest {
+		t.Errorf("wrong verifier: status = %d, want 400", status)
+	}
+	form.Set("code_verifier", verifier)
+	status, body := postForm(t, srv.URL+"/ims/token/v2", form)
+	if status != http.StatusOK || body["access_token"] == nil || body["refresh_token"] == nil || body["id_token"] == nil {
+		t.Fatalf("status = %d, body = %v, want access, refresh and ID tokens", status, body)
+	}
+
+	// Codes are single use.
+	if status, _ := postForm(t, srv.URL+"/ims/token/v2", form); status != http.StatusBadRequest {
+		t.Errorf("reused code: status = %d, want 400", status)
+	}
+}
+
+func TestLoginPage(t *testing.T) {
+	_, srv := newTestServer(t, Options{})
+	q := url.Values{"client_id": {"cid"}, "response_type": {"token"}, "redirect_uri": {"http://localhost/cb"},
+		"scope": {"openid"}}
+	res, err := http.Get(srv.URL + "/ims/authorize/v1?" + q.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusOK || !strings.HasPrefix(res.Header.Get("Content-Type"), "text/html") {
+		t.Fatalf("status = %d, content type = %q, want the login page", res.StatusCode, res.Header.Get("Content-Type"))
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	q.Set("user_id", "someone@AdobeID")
+	res, err = client.PostForm(srv.URL+"/ims/authorize/v1", q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+	location, _ := url.Parse(res.Header.Get("Location"))
+	fragment, _ := url.ParseQuery(location.Fragment)
+	if fragment.Get("access_token") == "" {
+		t.Errorf("location = %s, want an access token in the fragment", location)
+	}
+}
+
+func TestRefreshAndCascadingInvalidation(t *testing.T) {
+	s, srv := newTestServer(t, Options{})
+	code, err := s.IssueServiceCode([]string{"AdobeID"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := ims.Config{URL: srv.URL, ClientID: "cid", ClientSecret: "secret", AuthorizationCode: code,
+		Timeout: 5, NoCache: true}
+	if _, err := config.AuthorizeService(); err != nil {
+		t.Fatalf("service authorization: %v", err)
+	}
+
+	_, body := postForm(t, srv.URL+"/ims/token/v2", url.Values{"grant_type": {"authorization_code"},
+		"client_id": {"cid"}, "client_secret": {"secret"}, "code": {code}})
+	refreshToken, _ := body["refresh_token"].(string)
+
+	refresh := ims.Config{URL: srv.URL, ClientID: "cid", ClientSecret: "secret", RefreshToken: refreshToken, Timeout: 5}
+	refreshed, err := refresh.Refresh()
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	refresh.Cascading = true
+	if err := refresh.InvalidateToken(); err != nil {
+		t.Fatalf("invalidate: %v", err)
+	}
+	access := ims.Config{URL: srv.URL, ClientID: "cid", AccessToken: refreshed.AccessToken, Timeout: 5}
+	if info, err := access.ValidateToken(); err != nil || info.Valid {
+		t.Errorf("valid = %v, err = %v, want the access token invalidated with its refresh token", info.Valid, err)
+	}
+}
+
+func TestDeviceAuthorization(t *testing.T) {
+	_, srv := newTestServer(t, Options{})
+	status, auth := postForm(t, srv.URL+"/ims/device/authorize/v1", url.Values{"client_id": {"cid"}, "scope": {"openid"}})
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, body = %v", status, auth)
+	}
+	poll := url.Values{"grant_type": {deviceCodeGrantType}, "client_id": {"cid"}, "device_code": {auth["device_code"].(string)}}
+
+	if _, body := postForm(t, srv.URL+"/ims/token/v3", poll); body["error"] != "authorization_pending" {
+		t.Errorf("error = %v, want authorization_pending", body["error"])
+	}
+	res, err := http.PostForm(auth["verification_uri"].(string), url.Values{"user_code": {auth["user_code"].(string)},
+		"action": {"approve"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+	if status, body := postForm(t, srv.URL+"/ims/token/v3", poll); status != http.StatusOK || body["access_token"] == nil {
+		t.Errorf("status = %d, body = %v, want an access token", status, body)
+	}
+}
+
+func TestRegisteredClientSecret(t *testing.T) {
+	_, srv := newTestServer(t, Options{})
+	config := ims.Config{URL: srv.URL, ClientName: "app", RedirectURIs: []string{"https://example.com/cb"},
+		Scopes: []string{"openid"}, Timeout: 5}
+	client, err := config.DCRRegister()
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}, "client_id": {client.ClientID}, "client_secret": {"wrong"}}
+	if status, _ := postForm(t, srv.URL+"/ims/token/v2", form); status != http.StatusUnauthorized {
+		t.Errorf("wrong secret: status = %d, want 401", status)
+	}
+	form.Set("client_secret", client.ClientSecret)
+	if status, _ := postForm(t, srv.URL+"/ims/token/v2", form); status != http.StatusOK {
+		t.Errorf("registered secret: status = %d, want 200", status)
+	}
+}
+
+func TestClientConfigurationLifecycle(t *testing.T) {
+	_, srv := newTestServer(t, Options{})
+	config := ims.Config{URL: srv.URL, ClientName: "app", RedirectURIs: []string{"https://example.com/cb"},
+		Scopes: []string{"openid"}, Timeout: 5}
+	registered, err := config.DCRRegister()
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if registered.RegistrationAccessToken == "" || registered.RegistrationClientURI != srv.URL+"/ims/register/"+registered.ClientID {
+		t.Fatalf("registration = %+v, want a registration access token and URI", registered)
+	}
+
+	manage := ims.Config{URL: srv.URL, ClientID: registered.ClientID, Timeout: 5,
+		RegistrationAccessToken: registered.RegistrationAccessToken}
+	if _, err := (ims.Config{URL: srv.URL, ClientID: registered.ClientID, Timeout: 5,
+		RegistrationAccessToken: "wrong"}).DCRGet(); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("get with a wrong token: error = %v, want a 401", err)
+	}
+	got, err := manage.DCRGet()
+	if err != nil || got.ClientName != "app" || got.ClientSecret != registered.ClientSecret {
+		t.Fatalf("get: %+v, err = %v, want the registered client", got, err)
+	}
+
+	update := manage
+	update.Scopes = []string{"openid", "profile"}
+	got, err = update.DCRUpdate()
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if got.Scope != "openid profile" || got.ClientName != "app" || len(got.RedirectURIs) != 1 {
+		t.Errorf("update: %+v, want the new scopes and the other metadata unchanged", got)
+	}
+
+	if err := manage.DCRDelete(); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := manage.DCRGet(); err == nil {
+		t.Error("get after deletion: expected an error")
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package secretstore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	fileFormatVersion = 1
+	kdfIterations     = 600000
+	// maxKDFIterations bounds the iterations read from the file, so that a
+	// crafted file cannot stall the commands reading the store.
+	maxKDFIterations = 10 * kdfIterations
+	keyLength        = 32
+	saltLength       = 16
+)
+
+// FileStore keeps the secrets in a file encrypted with AES-256-GCM, with a key
+// derived from a passphrase with PBKDF2-HMAC-SHA256.
+type FileStore struct {
+	Path       string
+	Passphrase PassphraseFunc
+
+	once       sync.Once
+	passphrase string
+	err        error
+}
+
+// encryptedFile is the format of the file. The salt is renewed on each write.
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+func (s *FileStore) Get(name string) (string, error) {
+	secrets, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	secret, ok := secrets[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return secret, nil
+}
+
+func (s *FileStore) Set(name, secret string) error {
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+	secrets[name] = secret
+	return s.save(secrets)
+}
+
+func (s *FileStore) Delete(name string) error {
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	delete(secrets, name)
+	return s.save(secrets)
+}
+
+func (s *FileStore) List() ([]string, error) {
+	secrets, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// getPassphrase returns the passphrase, asked once. create tells whether the
+// file is about to be created.
+func (s *FileStore) getPassphrase(create bool) (string, error) {
+	s.once.Do(func() {
+		if s.Passphrase == nil {
+			s.err = fmt.Errorf("missing secret store passphrase")
+			return
+		}
+		s.passphrase, s.err = s.Passphrase(create)
+		if s.err == nil && s.passphrase == "" {
+			s.err = fmt.Errorf("missing secret store passphrase")
+		}
+	})
+	return s.passphrase, s.err
+}
+
+// load decrypts the file. A missing file is an empty store.
+func (s *FileStore) load() (map[string]string, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading the secret store: %w", err)
+	}
+	var f encryptedFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("error parsing the secret store %s: %w", s.Path, err)
+	}
+	if f.Version != fileFormatVersion {
+		return nil, fmt.Errorf("unsupported secret store version %d", f.Version)
+	}
+
+	if f.Iterations <= 0 || f.Iterations > maxKDFIterations {
+		return nil, fmt.Errorf("invalid iterations %d in the secret store %s", f.Iterations, s.Path)
+	}
+
+	passphrase, err := s.getPassphrase(false)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, f.Salt, f.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("unable to decrypt the secret store, wrong passphrase?")
+	}
+	plain, err := gcm.Open(nil, f.Nonce, f.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt the secret store, wrong passphrase?")
+	}
+	defer clear(plain)
+
+	secrets := map[string]string{}
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, fmt.Errorf("error parsing the secret store %s: %w", s.Path, err)
+	}
+	return secrets, nil
+}
+
+func (s *FileStore) save(secrets map[string]string) error {
+	// The passphrase was asked by load, unless the file does not exist yet.
+	passphrase, err := s.getPassphrase(true)
+	if err != nil {
+		return err
+	}
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("error encoding the secrets: %w", err)
+	}
+	defer clear(plain)
+
+	f := encryptedFile{Version: fileFormatVersion, Iterations: kdfIterations, Salt: make([]byte, saltLength)}
+	if _, err := rand.Read(f.Salt); err != nil {
+		return fmt.Errorf("error generating the salt: %w", err)
+	}
+	gcm, err := newGCM(passphrase, f.Salt, f.Iterations)
+	if err != nil {
+		return err
+	}
+	f.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(f.Nonce); err != nil {
+		return fmt.Errorf("error generating the nonce: %w", err)
+	}
+	f.Data = gcm.Seal(nil, f.Nonce, plain, nil)
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding the secret store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o700); err != nil {
+		return fmt.Errorf("error creating the secret store directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), ".secrets-*")
+	if err != nil {
+		return fmt.Errorf("error writing the secret store: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.Path)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing the secret store: %w", err)
+	}
+	return nil
+}
+
+func newGCM(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	if iterations <= 0 || len(salt) == 0 {
+		return nil, fmt.Errorf("invalid key derivation parameters in the secret store")
+	}
+	key := pbkdf2.Key([]byte(passphrase), salt, iterations, keyLength, sha256.New)
+	defer clear(key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package secretstore
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "imscli", "secrets.enc")
+	var created []bool
+	passphrase := func(create bool) (string, error) {
+		created = append(created, create)
+		return "correct horse", nil
+	}
+	store := &FileStore{Path: path, Passphrase: passphrase}
+
+	if _, err := store.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("get from an empty store: error = %v, want ErrNotFound", err)
+	}
+	for _, name := range []string{"b", "a"} {
+		if err := store.Set(name, "secret-"+name); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	}
+
+	if !slices.Equal(created, []bool{true}) {
+		t.Errorf("passphrase asked with create = %v, want once for the new file", created)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret-a") {
+		t.Error("the secrets are stored in plain text")
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm()&0o077 != 0 {
+		t.Errorf("mode = %04o, want no access for other users", info.Mode().Perm())
+	}
+
+	reopened := &FileStore{Path: path, Passphrase: passphrase}
+	if got, err := reopened.Get("a"); err != nil || got != "secret-a" {
+		t.Errorf("get = %q, %v, want secret-a", got, err)
+	}
+	if names, err := reopened.List(); err != nil || !slices.Equal(names, []string{"a", "b"}) {
+		t.Errorf("list = %v, %v, want [a b]", names, err)
+	}
+	if err := reopened.Delete("a"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := reopened.Delete("a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second delete: error = %v, want ErrNotFound", err)
+	}
+
+	if !slices.Equal(created, []bool{true, false}) {
+		t.Errorf("passphrase asked with create = %v, want once more for the existing file", created)
+	}
+
+	wrong := &FileStore{Path: path, Passphrase: func(bool) (string, error) { return "wrong", nil }}
+	if _, err := wrong.Get("b"); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("wrong passphrase: error = %v", err)
+	}
+}
+
+func TestFileStore_Malformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	passphrase := func(bool) (string, error) { return "correct horse", nil }
+	if err := (&FileStore{Path: path, Passphrase: passphrase}).Set("a", "secret"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var valid encryptedFile
+	if err := json.Unmarshal(data, &valid); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(f *encryptedFile)
+		wantErr string
+	}{
+		{name: "truncated nonce", modify: func(f *encryptedFile) { f.Nonce = f.Nonce[:4] }, wantErr: "unable to decrypt"},
+		{name: "missing nonce", modify: func(f *encryptedFile) { f.Nonce = nil }, wantErr: "unable to decrypt"},
+		{name: "no iterations", modify: func(f *encryptedFile) { f.Iterations = 0 }, wantErr: "invalid iterations"},
+		{name: "too many iterations", modify: func(f *encryptedFile) { f.Iterations = 1 << 40 }, wantErr: "invalid iterations"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := valid
+			tt.modify(&f)
+			data, err := json.Marshal(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			_, err = (&FileStore{Path: path, Passphrase: passphrase}).Get("a")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidName(t *testing.T) {
+	for name, valid := range map[string]bool{"my-client.secret_1": true, "": false, "a b": false, "a/b": false} {
+		if err := ValidName(name); (err == nil) != valid {
+			t.Errorf("ValidName(%q) = %v, want valid = %v", name, err, valid)
+		}
+	}
+}
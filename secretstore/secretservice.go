@@ -0,0 +1,112 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+package secretstore
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+const (
+	// secretTool is the libsecret command line client of the Secret Service.
+	secretTool = "secret-tool"
+	// serviceAttribute identifies the items created by imscli.
+	serviceAttribute = "imscli"
+)
+
+// secretService stores the secrets in the freedesktop Secret Service through
+// secret-tool, as items with the attributes service=imscli and name=<name>.
+type secretService struct{}
+
+// secretServiceAvailable reports whether secret-tool is installed and a
+// session bus is running to reach the service.
+func secretServiceAvailable() bool {
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return false
+	}
+	_, err := exec.LookPath(secretTool)
+	return err == nil
+}
+
+func (secretService) Get(name string) (string, error) {
+	out, err := runSecretTool(nil, "lookup", "service", serviceAttribute, "name", name)
+	if err != nil {
+		// secret-tool exits with 1 and prints nothing when there is no such item.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(out) == 0 {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		return "", err
+	}
+	defer clear(out)
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+func (secretService) Set(name, secret string) error {
+	_, err := runSecretTool(strings.NewReader(secret), "store", "--label", "imscli "+name,
+		"service", serviceAttribute, "name", name)
+	return err
+}
+
+func (s secretService) Delete(name string) error {
+	if _, err := s.Get(name); err != nil {
+		return err
+	}
+	_, err := runSecretTool(nil, "clear", "service", serviceAttribute, "name", name)
+	return err
+}
+
+// List parses the attributes printed by secret-tool search, e.g.
+// "attribute.name = my-client".
+func (secretService) List() ([]string, error) {
+	out, err := runSecretTool(nil, "search", "--all", "service", serviceAttribute)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(out) == 0 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// The secrets are printed along with the attributes.
+	defer clear(out)
+	var names []string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if name, ok := strings.CutPrefix(scanner.Text(), "attribute.name = "); ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+func runSecretTool(stdin *strings.Reader, args ...string) ([]byte, error) {
+	c := exec.Command(secretTool, args...)
+	if stdin != nil {
+		c.Stdin = stdin
+	}
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return out, fmt.Errorf("%s %s: %s: %w", secretTool, args[0], msg, err)
+		}
+		return out, fmt.Errorf("%s %s: %w", secretTool, args[0], err)
+	}
+	return out, nil
+}
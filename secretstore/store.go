@@ -0,0 +1,89 @@
+// Copyright 2026 Adobe. All rights reserved.
+// This file is licensed to you under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under
+// the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR REPRESENTATIONS
+// OF ANY KIND, either express or implied. See the License for the specific language
+// governing permissions and limitations under the License.
+
+// Package secretstore keeps the secrets used by imscli, like client secrets
+// and refresh tokens, out of the configuration file. Secrets are stored by
+// name in an encrypted file or in the freedesktop Secret Service (GNOME
+// Keyring, KWallet), and referenced from the configuration as keyring:<name>.
+package secretstore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Backends accepted by Open. Auto selects the Secret Service when it is
+// available, and the encrypted file otherwise.
+const (
+	BackendAuto          = "auto"
+	BackendFile          = "file"
+	BackendSecretService = "secret-service"
+)
+
+// ErrNotFound is returned when no secret is stored with the given name.
+var ErrNotFound = errors.New("secret not found")
+
+// SecretStore stores secrets by name.
+type SecretStore interface {
+	Get(name string) (string, error)
+	Set(name, secret string) error
+	Delete(name string) error
+	List() ([]string, error)
+}
+
+// PassphraseFunc returns the passphrase of the encrypted file. It is only
+// called when the file is actually read or written, create telling whether
+// the file does not exist yet, so that a new passphrase can be confirmed.
+type PassphraseFunc func(create bool) (string, error)
+
+// Open returns the secret store of the given backend.
+func Open(backend string, passphrase PassphraseFunc) (SecretStore, error) {
+	switch backend {
+	case "", BackendAuto:
+		if secretServiceAvailable() {
+			return secretService{}, nil
+		}
+		return openFileStore(passphrase)
+	case BackendFile:
+		return openFileStore(passphrase)
+	case BackendSecretService:
+		if _, err := exec.LookPath(secretTool); err != nil {
+			return nil, fmt.Errorf("the Secret Service backend needs the %s command: %w", secretTool, err)
+		}
+		return secretService{}, nil
+	default:
+		return nil, fmt.Errorf("unknown secret store %q, supported stores are %s, %s and %s", backend,
+			BackendAuto, BackendFile, BackendSecretService)
+	}
+}
+
+func openFileStore(passphrase PassphraseFunc) (SecretStore, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("unable to find configuration directory: %w", err)
+	}
+	return &FileStore{Path: filepath.Join(configDir, "imscli", "secrets.enc"), Passphrase: passphrase}, nil
+}
+
+// ValidName checks the name of a secret.
+func ValidName(name string) error {
+	if name == "" {
+		return fmt.Errorf("missing secret name")
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return fmt.Errorf("invalid secret name %q, use letters, digits, dots, dashes and underscores", name)
+		}
+	}
+	return nil
+}